@@ -0,0 +1,200 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DavidGamba/go-getoptions/completion"
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// Arg - define a positional argument.
+// Arguments are matched in the order they are defined against the remaining
+// (non option) arguments returned by Parse.
+// The remaining arguments are still returned by Parse unmodified.
+//
+// The supported types are option.StringType, option.IntType,
+// option.Float64Type and option.DurationType.
+// A variadic tail that collects the rest of the arguments is defined with
+// option.StringRepeatType, option.IntRepeatType or option.DurationRepeatType.
+// For example:
+//
+//     opt.Arg("src", option.StringType, true, opt.CompleteFiles())
+//     opt.Arg("dst", option.StringRepeatType, false, opt.Description("Destinations"))
+//
+// Results in the synopsis `<src> [<dst>...]` and an ARGUMENTS help section.
+// The values are available with `opt.ArgValue(name)`.
+//
+// Required arguments must be defined before optional ones and the variadic
+// tail must be the last argument.
+//
+// NOTE: Arguments are only checked when the GetOpt object has no commands.
+func (gopt *GetOpt) Arg(name string, argType option.Type, required bool, fns ...ModifyFn) *GetOpt {
+	for _, arg := range gopt.positionals {
+		if arg.Name == name {
+			panic(fmt.Sprintf("Argument '%s' is already defined", name))
+		}
+		if isArgTail(arg) {
+			panic(fmt.Sprintf("Argument '%s' defined after variadic argument '%s'", name, arg.Name))
+		}
+		if required && !arg.IsRequired {
+			panic(fmt.Sprintf("Required argument '%s' defined after optional argument '%s'", name, arg.Name))
+		}
+	}
+	var opt *option.Option
+	switch argType {
+	case option.StringType:
+		opt = option.New(name, argType, new(string))
+	case option.IntType:
+		opt = option.New(name, argType, new(int))
+	case option.Float64Type:
+		opt = option.New(name, argType, new(float64))
+	case option.DurationType:
+		opt = option.New(name, argType, new(time.Duration))
+	case option.StringRepeatType:
+		opt = option.New(name, argType, &[]string{})
+	case option.IntRepeatType:
+		opt = option.New(name, argType, &[]int{})
+	case option.DurationRepeatType:
+		opt = option.New(name, argType, &[]time.Duration{})
+	default:
+		panic(fmt.Sprintf("Argument '%s' type not supported", name))
+	}
+	opt.IsRequired = required
+	opt.HelpArgName = name
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.positionals = append(gopt.positionals, opt)
+
+	// Completion
+	var node *completion.Node
+	switch opt.Completion {
+	case option.FileCompletion:
+		node = completion.NewNode(".", completion.FileListNode, nil)
+	case option.DirCompletion:
+		node = completion.NewNode(".", completion.DirListNode, nil)
+	case option.ListCompletion:
+		node = completion.NewNode(name, completion.CustomNode, opt.CompletionList)
+	default:
+		node = completion.NewNode(name, completion.CustomNode, opt.ValidValues)
+	}
+	gopt.completion.Positionals = append(gopt.completion.Positionals, node)
+	gopt.completion.Variadic = isArgTail(opt)
+	return gopt
+}
+
+// Args - Defines the minimum and maximum amount of positional arguments.
+// Use a max of -1 for no limit.
+//
+// By default, when arguments are defined with Arg, the maximum is the amount
+// of defined arguments unless there is a variadic tail.
+//
+// NOTE: Arguments are only checked when the GetOpt object has no commands.
+func (gopt *GetOpt) Args(min, max int) *GetOpt {
+	if min < 0 {
+		panic("Args min should be >= 0")
+	}
+	if max >= 0 && max < min {
+		panic("Args max should be >= min or -1")
+	}
+	gopt.argsLimits = []int{min, max}
+	return gopt
+}
+
+// ArgValue - Returns the value of the positional argument with the given name.
+// If the argument wasn't given it returns its zero value.
+// If the `name` is an argument that wasn't declared it will return nil.
+func (gopt *GetOpt) ArgValue(name string) interface{} {
+	for _, arg := range gopt.positionals {
+		if arg.Name == name {
+			return arg.Value()
+		}
+	}
+	return nil
+}
+
+// CompleteFiles - Use file and directory names as completions for a positional argument.
+func (gopt *GetOpt) CompleteFiles() ModifyFn {
+	return func(opt *option.Option) {
+		opt.Completion = option.FileCompletion
+	}
+}
+
+// CompleteDirs - Use directory names as completions for a positional argument.
+func (gopt *GetOpt) CompleteDirs() ModifyFn {
+	return func(opt *option.Option) {
+		opt.Completion = option.DirCompletion
+	}
+}
+
+// CompleteList - Use the given list as completions for a positional argument.
+// By default, arguments with ValidValues complete their valid values.
+func (gopt *GetOpt) CompleteList(list ...string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.Completion = option.ListCompletion
+		opt.CompletionList = list
+	}
+}
+
+func isArgTail(arg *option.Option) bool {
+	switch arg.OptType {
+	case option.StringRepeatType, option.IntRepeatType, option.DurationRepeatType:
+		return true
+	}
+	return false
+}
+
+// parseArgs - Saves the remaining arguments into the positional arguments and
+// checks the amount of arguments.
+func (gopt *GetOpt) parseArgs(remaining []string) error {
+	if len(gopt.commands) > 0 {
+		return nil
+	}
+	min, max := 0, -1
+	if len(gopt.positionals) > 0 && !isArgTail(gopt.positionals[len(gopt.positionals)-1]) {
+		max = len(gopt.positionals)
+	}
+	if gopt.argsLimits != nil {
+		min, max = gopt.argsLimits[0], gopt.argsLimits[1]
+	}
+	i := 0
+	for _, arg := range gopt.positionals {
+		if i >= len(remaining) {
+			if arg.IsRequired {
+				return fmt.Errorf(text.ErrorMissingRequiredArgument, arg.Name)
+			}
+			break
+		}
+		arg.SetCalled(arg.Name)
+		values := remaining[i : i+1]
+		if isArgTail(arg) {
+			values = remaining[i:]
+		}
+		i += len(values)
+		err := arg.Save(values...)
+		if err != nil {
+			return err
+		}
+		err = arg.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	if len(remaining) < min {
+		return fmt.Errorf(text.ErrorTooFewArguments, len(remaining), min)
+	}
+	if max >= 0 && len(remaining) > max {
+		return fmt.Errorf(text.ErrorTooManyArguments, len(remaining), max)
+	}
+	return nil
+}
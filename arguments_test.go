@@ -0,0 +1,285 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+func TestArgs(t *testing.T) {
+	t.Run("values", func(t *testing.T) {
+		opt := New()
+		opt.Bool("force", false)
+		opt.Arg("src", option.StringType, true)
+		opt.Arg("count", option.IntType, true)
+		opt.Arg("ratio", option.Float64Type, true)
+		opt.Arg("timeout", option.DurationType, true)
+		opt.Arg("dst", option.StringRepeatType, false)
+		remaining, err := opt.Parse([]string{"a", "--force", "3", "0.5", "1m", "b", "c"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(remaining, []string{"a", "3", "0.5", "1m", "b", "c"}) {
+			t.Errorf("Unexpected remaining: %v", remaining)
+		}
+		if opt.ArgValue("src") != "a" || opt.ArgValue("count") != 3 || opt.ArgValue("ratio") != 0.5 || opt.ArgValue("timeout") != time.Minute {
+			t.Errorf("Unexpected values: %v, %v, %v, %v", opt.ArgValue("src"), opt.ArgValue("count"), opt.ArgValue("ratio"), opt.ArgValue("timeout"))
+		}
+		if !reflect.DeepEqual(opt.ArgValue("dst"), []string{"b", "c"}) {
+			t.Errorf("Unexpected value: %v", opt.ArgValue("dst"))
+		}
+		if opt.ArgValue("unknown") != nil {
+			t.Errorf("Unexpected value: %v", opt.ArgValue("unknown"))
+		}
+	})
+
+	t.Run("tail types", func(t *testing.T) {
+		opt := New()
+		opt.Arg("ints", option.IntRepeatType, true)
+		_, err := opt.Parse([]string{"1", "2..4"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(opt.ArgValue("ints"), []int{1, 2, 3, 4}) {
+			t.Errorf("Unexpected value: %v", opt.ArgValue("ints"))
+		}
+		opt = New()
+		opt.Arg("durations", option.DurationRepeatType, false)
+		_, err = opt.Parse([]string{"1s", "2m"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(opt.ArgValue("durations"), []time.Duration{time.Second, 2 * time.Minute}) {
+			t.Errorf("Unexpected value: %v", opt.ArgValue("durations"))
+		}
+	})
+
+	t.Run("optional not given", func(t *testing.T) {
+		opt := New()
+		opt.Arg("src", option.StringType, true)
+		opt.Arg("dst", option.StringType, false)
+		opt.Arg("rest", option.StringRepeatType, false)
+		_, err := opt.Parse([]string{"a"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if opt.ArgValue("dst") != "" || !reflect.DeepEqual(opt.ArgValue("rest"), []string{}) {
+			t.Errorf("Unexpected values: %v, %v", opt.ArgValue("dst"), opt.ArgValue("rest"))
+		}
+	})
+
+	t.Run("commands", func(t *testing.T) {
+		opt := New()
+		opt.Arg("src", option.StringType, true)
+		opt.NewCommand("cmd", "")
+		remaining, err := opt.Parse([]string{"cmd", "a", "b"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(remaining, []string{"cmd", "a", "b"}) {
+			t.Errorf("Unexpected remaining: %v", remaining)
+		}
+	})
+
+	tests := []struct {
+		name string
+		def  func(*GetOpt)
+		args []string
+		err  string
+	}{
+		{"missing required", func(opt *GetOpt) {
+			opt.Arg("src", option.StringType, true)
+			opt.Arg("dst", option.StringType, true)
+		}, []string{"a"}, fmt.Sprintf(text.ErrorMissingRequiredArgument, "dst")},
+		{"missing required tail", func(opt *GetOpt) {
+			opt.Arg("src", option.StringRepeatType, true)
+		}, []string{}, fmt.Sprintf(text.ErrorMissingRequiredArgument, "src")},
+		{"too many", func(opt *GetOpt) {
+			opt.Arg("src", option.StringType, true)
+		}, []string{"a", "b"}, fmt.Sprintf(text.ErrorTooManyArguments, 2, 1)},
+		{"conversion", func(opt *GetOpt) {
+			opt.Arg("count", option.IntType, true)
+		}, []string{"x"}, fmt.Sprintf(text.ErrorConvertToInt, "count", "x")},
+		{"tail conversion", func(opt *GetOpt) {
+			opt.Arg("count", option.IntRepeatType, true)
+		}, []string{"1", "x"}, fmt.Sprintf(text.ErrorConvertToInt, "count", "x")},
+		{"valid values", func(opt *GetOpt) {
+			opt.Arg("format", option.StringType, true, opt.ValidValues("json", "yaml"))
+		}, []string{"xml"}, fmt.Sprintf(text.ErrorArgumentNotValidValue, "format", "xml", []string{"json", "yaml"})},
+		{"validator", func(opt *GetOpt) {
+			opt.Arg("count", option.IntType, true, opt.Max(5))
+		}, []string{"6"}, fmt.Sprintf(text.ErrorValueGreaterThanMax, "count", 6, 5)},
+		{"args min", func(opt *GetOpt) {
+			opt.Args(2, -1)
+		}, []string{"a"}, fmt.Sprintf(text.ErrorTooFewArguments, 1, 2)},
+		{"args max", func(opt *GetOpt) {
+			opt.Args(0, 1)
+		}, []string{"a", "b"}, fmt.Sprintf(text.ErrorTooManyArguments, 2, 1)},
+		{"args max with arg", func(opt *GetOpt) {
+			opt.Arg("src", option.StringRepeatType, true)
+			opt.Args(1, 2)
+		}, []string{"a", "b", "c"}, fmt.Sprintf(text.ErrorTooManyArguments, 3, 2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := New()
+			tt.def(opt)
+			_, err := opt.Parse(tt.args)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+		})
+	}
+
+	t.Run("args limits", func(t *testing.T) {
+		opt := New()
+		opt.Arg("src", option.StringType, true)
+		opt.Args(1, -1)
+		remaining, err := opt.Parse([]string{"a", "b", "c"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if len(remaining) != 3 || opt.ArgValue("src") != "a" {
+			t.Errorf("Unexpected values: %v, %v", remaining, opt.ArgValue("src"))
+		}
+	})
+
+	t.Run("dispatch", func(t *testing.T) {
+		opt := New()
+		cmd := opt.NewCommand("cp", "")
+		cmd.Arg("src", option.StringType, true)
+		cmd.SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		_, err := opt.Parse([]string{"cp"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		err = opt.Dispatch(context.Background(), "help", []string{"cp"})
+		expected := fmt.Sprintf(text.ErrorMissingRequiredArgument, "src")
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt := New()
+		opt.Bool("force", false)
+		opt.Arg("src", option.StringType, true, opt.Description("Source file"))
+		opt.Arg("format", option.StringType, false, opt.ValidValues("json", "yaml"))
+		opt.Arg("dst", option.StringRepeatType, false, opt.ArgName("destination"))
+		got := opt.Help()
+		expected := `SYNOPSIS:
+    go-getoptions.test [--force] <src> [<format>] [<destination>...]
+
+ARGUMENTS:
+    <src>                 Source file
+
+    [<format>]            (valid values: json|yaml)
+
+    [<destination>...]
+
+OPTIONS:
+    --force    (default: false)
+
+`
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+		}
+
+		opt.HelpSynopsisArgs("<files>")
+		got = opt.Help(HelpSynopsis)
+		expected = `SYNOPSIS:
+    go-getoptions.test [--force] <files>
+
+`
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			compLine string
+			expected string
+		}{
+			{"list", "test cp ", "json\nyaml\n"},
+			{"valid values", "test cp json ", "a\nb\n"},
+			{"dirs", "test cp json a completion/test/test_tree/b", "completion/test/test_tree/bDir1/\ncompletion/test/test_tree/bDir2/\n"},
+			{"files", "test cp json a completion/test/test_tree/ completion/test/test_tree/c", "completion/test/test_tree/cFile1\ncompletion/test/test_tree/cFile2\n"},
+			{"variadic", "test cp json a completion/test/test_tree/ x completion/test/test_tree/c", "completion/test/test_tree/cFile1\ncompletion/test/test_tree/cFile2\n"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				exitFn = func(code int) {}
+				defer func() { exitFn = os.Exit }()
+				opt := New()
+				cmd := opt.NewCommand("cp", "")
+				cmd.Arg("format", option.StringType, true, cmd.CompleteList("json", "yaml"))
+				cmd.Arg("kind", option.StringType, true, cmd.ValidValues("a", "b"))
+				cmd.Arg("dir", option.StringType, true, cmd.CompleteDirs())
+				cmd.Arg("files", option.StringRepeatType, true, cmd.CompleteFiles())
+				buf := new(bytes.Buffer)
+				completionWriter = buf
+				defer func() { completionWriter = os.Stdout; os.Setenv("COMP_LINE", "") }()
+				os.Setenv("COMP_LINE", tt.compLine)
+				_, err := opt.Parse([]string{})
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				if buf.String() != tt.expected {
+					t.Errorf("Unexpected completion: %q", buf.String())
+				}
+			})
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		tests := []struct {
+			name string
+			fn   func(opt *GetOpt)
+		}{
+			{"duplicate", func(opt *GetOpt) {
+				opt.Arg("src", option.StringType, true)
+				opt.Arg("src", option.StringType, true)
+			}},
+			{"after tail", func(opt *GetOpt) {
+				opt.Arg("src", option.StringRepeatType, true)
+				opt.Arg("dst", option.StringType, false)
+			}},
+			{"required after optional", func(opt *GetOpt) {
+				opt.Arg("src", option.StringType, false)
+				opt.Arg("dst", option.StringType, true)
+			}},
+			{"unsupported type", func(opt *GetOpt) {
+				opt.Arg("src", option.StringMapType, true)
+			}},
+			{"args min", func(opt *GetOpt) { opt.Args(-1, 1) }},
+			{"args max", func(opt *GetOpt) { opt.Args(2, 1) }},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("%s did not panic", tt.name)
+					}
+				}()
+				tt.fn(New())
+			})
+		}
+	})
+}
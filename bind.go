@@ -0,0 +1,272 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// BindTag - Struct tag key read by Bind.
+var BindTag = "getoptions"
+
+// bindRunner - Implemented by nested command structs to define the command entry point.
+type bindRunner interface {
+	Run(ctx context.Context, opt *GetOpt, args []string) error
+}
+
+// bindTag - Parsed struct tag.
+type bindTag struct {
+	name     string
+	aliases  []string
+	env      string
+	required bool
+	command  bool
+	min, max int
+	desc     string
+}
+
+// Bind - define an option for every exported field of the given struct pointer.
+// The current field values are used as the defaults.
+//
+// Options are configured with the `getoptions` struct tag.
+// The first element is the option name, when empty the name is the field name in kebab case (MaxRetries -> max-retries).
+// The remaining elements are:
+//
+//     alias=H|host-name  option aliases separated by |
+//     env=HOST           read the option from the environment variable, see GetEnv
+//     required           the option is required, see Required
+//     min=1,max=3        min and max arguments for slice and map fields, 1 by default
+//     command            nested struct field defines a command
+//     desc=...           description, must be the last element as it can contain commas
+//
+// Use `getoptions:"-"` to skip a field.
+// For example:
+//
+//     type Config struct {
+//         Host    string            `getoptions:"host,alias=H,env=HOST,required,desc=Server host"`
+//         Port    int               `getoptions:",desc=Server port"`
+//         Tags    []string          `getoptions:"tag,max=5"`
+//         DB      struct{ User string }
+//         Deploy  DeployConfig      `getoptions:"deploy,command,desc=Deploy the app"`
+//     }
+//     cfg := Config{Port: 8080}
+//     err := opt.Bind(&cfg)
+//
+// Nested struct fields define options prefixed with the field name (--db-user), embedded struct fields are flattened.
+// Unexported fields are ignored.
+// When tagged as a command, a command is defined with the struct fields as its options.
+// If the command struct pointer implements `Run(ctx context.Context, opt *GetOpt, args []string) error`, it is set as the CommandFn.
+//
+// Supported field types are bool, string, int, int64, uint, uint64, float32, float64, time.Duration, time.Time,
+// []string, []int, []uint, []float64, []time.Duration, map[string]string and types whose pointer implements Value.
+// Slice and map fields keep their current values and append the values passed in the command line.
+// Other field types return an error.
+func (gopt *GetOpt) Bind(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Bind requires a non nil pointer to a struct, got '%T'", v)
+	}
+	return gopt.bindStruct(rv.Elem(), "")
+}
+
+func (gopt *GetOpt) bindStruct(rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		// Exported fields of unexported embedded structs are still bound.
+		if field.PkgPath != "" && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+		tag, skip, err := parseBindTag(field)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+		fv := rv.Field(i)
+		if tag.command {
+			if field.Type.Kind() != reflect.Struct {
+				return fmt.Errorf("Bind field '%s' tagged as command must be a struct, got '%s'", field.Name, field.Type)
+			}
+			cmd := gopt.NewCommand(tag.name, tag.desc)
+			if fv.CanInterface() {
+				if r, ok := fv.Addr().Interface().(bindRunner); ok {
+					cmd.SetCommandFn(r.Run)
+				}
+			}
+			err := cmd.bindStruct(fv, "")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if isBindStruct(fv) {
+			nestedPrefix := prefix
+			if tag.name != "" {
+				nestedPrefix += tag.name + "-"
+			}
+			err := gopt.bindStruct(fv, nestedPrefix)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err = gopt.bindField(field, fv, prefix+tag.name, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gopt *GetOpt) bindField(field reflect.StructField, fv reflect.Value, name string, tag bindTag) error {
+	fns := []ModifyFn{}
+	if len(tag.aliases) > 0 {
+		fns = append(fns, gopt.Alias(tag.aliases...))
+	}
+	if tag.required {
+		fns = append(fns, gopt.Required())
+	}
+	if tag.desc != "" {
+		fns = append(fns, gopt.Description(tag.desc))
+	}
+	if tag.env != "" {
+		fns = append(fns, gopt.GetEnv(tag.env))
+	}
+	switch p := fv.Addr().Interface().(type) {
+	case Value:
+		gopt.Var(p, name, fns...)
+	case *bool:
+		gopt.BoolVar(p, name, *p, fns...)
+	case *string:
+		gopt.StringVar(p, name, *p, fns...)
+	case *int:
+		gopt.IntVar(p, name, *p, fns...)
+	case *int64:
+		OptVar(gopt, p, name, *p, fns...)
+	case *uint:
+		OptVar(gopt, p, name, *p, fns...)
+	case *uint64:
+		OptVar(gopt, p, name, *p, fns...)
+	case *float32:
+		OptVar(gopt, p, name, *p, fns...)
+	case *float64:
+		gopt.Float64Var(p, name, *p, fns...)
+	case *time.Duration:
+		gopt.DurationVar(p, name, *p, fns...)
+	case *time.Time:
+		gopt.TimeVar(p, name, *p, fns...)
+	case *[]string:
+		gopt.StringSliceVar(p, name, tag.min, tag.max, fns...)
+	case *[]int:
+		gopt.IntSliceVar(p, name, tag.min, tag.max, fns...)
+	case *[]uint:
+		OptSliceVar(gopt, p, name, tag.min, tag.max, fns...)
+	case *[]float64:
+		OptSliceVar(gopt, p, name, tag.min, tag.max, fns...)
+	case *[]time.Duration:
+		gopt.DurationSliceVar(p, name, tag.min, tag.max, fns...)
+	case *map[string]string:
+		gopt.StringMapVar(p, name, tag.min, tag.max, fns...)
+	default:
+		return fmt.Errorf("Bind field '%s' of type '%s' is not supported", field.Name, field.Type)
+	}
+	return nil
+}
+
+// isBindStruct - Indicates if the field is a nested struct rather than a struct option type like time.Time.
+func isBindStruct(fv reflect.Value) bool {
+	if fv.Kind() != reflect.Struct {
+		return false
+	}
+	if !fv.CanInterface() {
+		return true
+	}
+	switch fv.Addr().Interface().(type) {
+	case Value, *time.Time:
+		return false
+	}
+	return true
+}
+
+func parseBindTag(field reflect.StructField) (bindTag, bool, error) {
+	tag := bindTag{min: 1, max: 1}
+	s, _ := field.Tag.Lookup(BindTag)
+	if s == "-" {
+		return tag, true, nil
+	}
+	elements := strings.Split(s, ",")
+	tag.name = elements[0]
+	for i, e := range elements[1:] {
+		key, value := e, ""
+		if j := strings.Index(e, "="); j >= 0 {
+			key, value = e[:j], e[j+1:]
+		}
+		var err error
+		switch key {
+		case "alias":
+			tag.aliases = strings.Split(value, "|")
+		case "env":
+			tag.env = value
+		case "required":
+			tag.required = true
+		case "command":
+			tag.command = true
+		case "min":
+			tag.min, err = strconv.Atoi(value)
+		case "max":
+			tag.max, err = strconv.Atoi(value)
+		case "desc":
+			tag.desc = strings.Join(append([]string{value}, elements[i+2:]...), ",")
+			return tag.withName(field), false, nil
+		default:
+			return tag, false, fmt.Errorf("Bind field '%s' has unknown tag element '%s'", field.Name, e)
+		}
+		if err != nil {
+			return tag, false, fmt.Errorf("Bind field '%s' has invalid tag element '%s'", field.Name, e)
+		}
+	}
+	return tag.withName(field), false, nil
+}
+
+// withName - Sets the name from the field name when not given in the tag.
+// Embedded struct fields don't have a name so their options are not prefixed.
+func (tag bindTag) withName(field reflect.StructField) bindTag {
+	if tag.name != "" {
+		return tag
+	}
+	if field.Anonymous && field.Type.Kind() == reflect.Struct {
+		return tag
+	}
+	tag.name = kebabCase(field.Name)
+	return tag
+}
+
+// kebabCase - Converts a field name to an option name, for example: MaxRetries -> max-retries, DBHost -> db-host.
+func kebabCase(s string) string {
+	r := []rune(s)
+	out := []rune{}
+	for i, c := range r {
+		if unicode.IsUpper(c) {
+			if i > 0 && (unicode.IsLower(r[i-1]) || (i+1 < len(r) && unicode.IsLower(r[i+1]) && unicode.IsUpper(r[i-1]))) {
+				out = append(out, '-')
+			}
+			c = unicode.ToLower(c)
+		}
+		out = append(out, c)
+	}
+	return string(out)
+}
@@ -0,0 +1,222 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+type bindCommon struct {
+	Verbose bool
+}
+
+type bindDeploy struct {
+	Region string `getoptions:"region,env=BIND_REGION"`
+	ran    bool
+}
+
+func (d *bindDeploy) Run(ctx context.Context, opt *GetOpt, args []string) error {
+	d.ran = true
+	return nil
+}
+
+type bindConfig struct {
+	bindCommon
+	Host       string            `getoptions:"host,alias=H|hostname,required,desc=Server host, name or IP"`
+	Port       int               `getoptions:",desc=Server port"`
+	MaxRetries int64             `getoptions:""`
+	Ratio      float64           `getoptions:"ratio"`
+	Small      float32           `getoptions:"small"`
+	Count      uint              `getoptions:"count"`
+	Big        uint64            `getoptions:"big"`
+	Timeout    time.Duration     `getoptions:"timeout"`
+	Since      time.Time         `getoptions:"since"`
+	Tags       []string          `getoptions:"tag,min=1,max=3"`
+	IDs        []int             `getoptions:"id"`
+	UIDs       []uint            `getoptions:"uid"`
+	Weights    []float64         `getoptions:"weight"`
+	Waits      []time.Duration   `getoptions:"wait"`
+	Labels     map[string]string `getoptions:"label"`
+	Level      logLevel          `getoptions:"level"`
+	Ignored    string            `getoptions:"-"`
+	DB         struct {
+		User string
+		Pool struct {
+			Size int
+		}
+	}
+	Deploy bindDeploy `getoptions:"deploy,command,desc=Deploy the app"`
+	secret string
+}
+
+func TestBind(t *testing.T) {
+	t.Run("values", func(t *testing.T) {
+		cfg := bindConfig{Port: 8080, Level: "info", Tags: []string{"a"}}
+		opt := New()
+		err := opt.Bind(&cfg)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		_, err = opt.Parse([]string{
+			"--verbose", "-H", "example.com", "--max-retries=5",
+			"--ratio", "0.5", "--small", "1.5", "--count", "2", "--big", "3",
+			"--timeout", "1m", "--since", "2021-01-01T00:00:00Z",
+			"--tag", "b", "c", "--id", "1", "--id", "2", "--uid", "3", "--weight", "0.1", "--wait", "1s",
+			"--label", "k=v", "--level", "debug", "--db-user", "root", "--db-pool-size", "10",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := bindConfig{
+			bindCommon: bindCommon{Verbose: true},
+			Host:       "example.com",
+			Port:       8080,
+			MaxRetries: 5,
+			Ratio:      0.5,
+			Small:      1.5,
+			Count:      2,
+			Big:        3,
+			Timeout:    time.Minute,
+			Since:      time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			Tags:       []string{"a", "b", "c"},
+			IDs:        []int{1, 2},
+			UIDs:       []uint{3},
+			Weights:    []float64{0.1},
+			Waits:      []time.Duration{time.Second},
+			Labels:     map[string]string{"k": "v"},
+			Level:      "debug",
+		}
+		expected.DB.User = "root"
+		expected.DB.Pool.Size = 10
+		if !reflect.DeepEqual(cfg, expected) {
+			t.Errorf("Unexpected values:\n%+v\n%+v", cfg, expected)
+		}
+		if opt.Option("ignored") != nil || opt.Option("secret") != nil {
+			t.Errorf("Unexpected options defined")
+		}
+	})
+
+	t.Run("command", func(t *testing.T) {
+		os.Setenv("BIND_REGION", "us-west-2")
+		defer os.Unsetenv("BIND_REGION")
+		cfg := bindConfig{}
+		opt := New()
+		err := opt.Bind(&cfg)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		remaining, err := opt.Parse([]string{"deploy", "--host", "example.com"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.Dispatch(context.Background(), "help", remaining)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !cfg.Deploy.ran || cfg.Deploy.Region != "us-west-2" {
+			t.Errorf("Unexpected values: %+v", cfg.Deploy)
+		}
+	})
+
+	t.Run("required", func(t *testing.T) {
+		cfg := bindConfig{}
+		opt := New()
+		err := opt.Bind(&cfg)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		_, err = opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorMissingRequiredOption, "host") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		cfg := struct {
+			Host string `getoptions:"host,alias=H,desc=Server host, name or IP"`
+			Port int
+		}{Host: "localhost", Port: 8080}
+		opt := New()
+		err := opt.Bind(&cfg)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		got := opt.Help(HelpOptionList)
+		expected := `OPTIONS:
+    --host|-H <string>    Server host, name or IP (default: "localhost")
+
+    --port <int>          (default: 8080)
+
+`
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+		}
+	})
+
+	tests := []struct {
+		name string
+		v    interface{}
+		err  string
+	}{
+		{"not a pointer", bindConfig{}, "Bind requires a non nil pointer to a struct, got 'getoptions.bindConfig'"},
+		{"nil", (*bindConfig)(nil), "Bind requires a non nil pointer to a struct, got '*getoptions.bindConfig'"},
+		{"not a struct", new(string), "Bind requires a non nil pointer to a struct, got '*string'"},
+		{"unsupported", &struct{ C chan int }{}, "Bind field 'C' of type 'chan int' is not supported"},
+		{"unsupported nested", &struct{ N struct{ B []bool } }{}, "Bind field 'B' of type '[]bool' is not supported"},
+		{"unsupported command", &struct {
+			N struct{ B []bool } `getoptions:"n,command"`
+		}{}, "Bind field 'B' of type '[]bool' is not supported"},
+		{"command not struct", &struct {
+			N string `getoptions:"n,command"`
+		}{}, "Bind field 'N' tagged as command must be a struct, got 'string'"},
+		{"unknown tag element", &struct {
+			N string `getoptions:"n,foo"`
+		}{}, "Bind field 'N' has unknown tag element 'foo'"},
+		{"invalid tag element", &struct {
+			N []string `getoptions:"n,min=x"`
+		}{}, "Bind field 'N' has invalid tag element 'min=x'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := New()
+			err := opt.Bind(tt.v)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+		})
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Host", "host"},
+		{"MaxRetries", "max-retries"},
+		{"DBHost", "db-host"},
+		{"DB", "db"},
+		{"UserID", "user-id"},
+		{"A", "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := kebabCase(tt.input); got != tt.expected {
+				t.Errorf("kebabCase(%s) = %s, want %s", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BashScript - Returns the bash completion script for the program.
+// bash calls path with COMP_LINE set, the program is expected to write the CompLineComplete results.
+//
+// Source it from `~/.bashrc`.
+func BashScript(name, path string) string {
+	// bash evaluates the -C command, so the path is quoted twice.
+	return fmt.Sprintf(`# bash completion for %s
+complete -o default -C %s %s
+`, name, shellQuote(shellQuote(path)), shellQuote(name))
+}
+
+// shellQuote - Returns s as a single shell word.
+// Strings with only safe characters are returned as is, others are single quoted.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_@%+=:,./-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellFuncName - Returns s with the characters that aren't valid in a shell function name replaced with '_'.
+func shellFuncName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,74 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import "testing"
+
+func TestBashScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		program  string
+		path     string
+		expected string
+	}{
+		{"plain", "mygit", "/usr/local/bin/mygit",
+			"# bash completion for mygit\ncomplete -o default -C /usr/local/bin/mygit mygit\n"},
+		{"spaces", "my git", "/opt/my tools/my git",
+			"# bash completion for my git\ncomplete -o default -C ''\\''/opt/my tools/my git'\\''' 'my git'\n"},
+		{"quote", "it's", "/bin/it's",
+			"# bash completion for it's\ncomplete -o default -C ''\\''/bin/it'\\''\\'\\'''\\''s'\\''' 'it'\\''s'\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := BashScript(tt.program, tt.path)
+			if script != tt.expected {
+				t.Errorf("got = %q, want %q", script, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"prog", "prog"},
+		{"/usr/bin/my-prog_1.0", "/usr/bin/my-prog_1.0"},
+		{"", "''"},
+		{"a b", "'a b'"},
+		{"$(rm -rf)", "'$(rm -rf)'"},
+		{"it's", `'it'\''s'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := shellQuote(tt.input); got != tt.expected {
+				t.Errorf("got = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShellFuncName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"my_prog1", "my_prog1"},
+		{"my-prog.v2", "my_prog_v2"},
+		{"it's a prog", "it_s_a_prog"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := shellFuncName(tt.input); got != tt.expected {
+				t.Errorf("got = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -44,6 +44,19 @@ type Node struct {
 	Children []*Node
 	Entries  []string // Use as completions for OptionsNode and CustomNode Kind.
 	// TODO: Maybe add sibling completion that gets activated with = for options
+
+	// Positionals - Completion nodes for each positional argument of a command.
+	// When Variadic is set, the last node is used for all the remaining arguments.
+	Positionals []*Node
+	Variadic    bool
+
+	// IgnoreCase - Match command names, options and custom entries ignoring case.
+	IgnoreCase bool
+
+	// Description - Description of the CommandNode, shown by shells that support it.
+	Description string
+	// Descriptions - Descriptions of the option and custom entries of the node children, indexed by entry.
+	Descriptions map[string]string
 }
 
 // CompletionType -
@@ -65,10 +78,15 @@ const (
 	OptionsNode
 
 	// OptionsWithCompletion - Only enabled if prefix starts with -
+	// Its CustomNode children, named after an option (for example: --format),
+	// provide the completions for the option argument when called as --format=.
 	OptionsWithCompletion
 
 	// CustomNode -
 	CustomNode
+
+	// DirListNode - Same as FileListNode but only completes directories.
+	DirListNode
 )
 
 // NewNode -
@@ -77,9 +95,10 @@ func NewNode(name string, kind kind, entries []string) *Node {
 		entries = []string{}
 	}
 	return &Node{
-		Name:    name,
-		Kind:    kind,
-		Entries: entries,
+		Name:         name,
+		Kind:         kind,
+		Entries:      entries,
+		Descriptions: map[string]string{},
 	}
 }
 
@@ -89,11 +108,38 @@ func (n *Node) AddChild(node *Node) {
 	n.Children = append(n.Children, node)
 }
 
+// SetIgnoreCase - Sets IgnoreCase on the node, its children and positional argument nodes.
+func (n *Node) SetIgnoreCase(ignoreCase bool) {
+	n.IgnoreCase = ignoreCase
+	for _, child := range n.Children {
+		child.SetIgnoreCase(ignoreCase)
+	}
+	for _, child := range n.Positionals {
+		child.SetIgnoreCase(ignoreCase)
+	}
+}
+
+// equal - Compares the strings honoring IgnoreCase.
+func (n *Node) equal(s, t string) bool {
+	if n.IgnoreCase {
+		return strings.EqualFold(s, t)
+	}
+	return s == t
+}
+
+// hasPrefix - Same as strings.HasPrefix but honoring IgnoreCase.
+func (n *Node) hasPrefix(s, prefix string) bool {
+	if n.IgnoreCase {
+		return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+	}
+	return strings.HasPrefix(s, prefix)
+}
+
 // SelfCompletions -
 func (n *Node) SelfCompletions(prefix string) []string {
 	switch n.Kind {
 	case CommandNode:
-		if strings.HasPrefix(n.Name, prefix) {
+		if n.hasPrefix(n.Name, prefix) {
 			Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, []string{n.Name})
 			return []string{n.Name}
 		}
@@ -107,23 +153,36 @@ func (n *Node) SelfCompletions(prefix string) []string {
 		ff := discardByPrefix(files, ".")
 		Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, ff)
 		return ff
+	case DirListNode:
+		files, _ := listDir(n.Name, prefix)
+		dirs := []string{}
+		for _, e := range files {
+			if strings.HasSuffix(e, "/") && (strings.HasPrefix(prefix, ".") || !strings.HasPrefix(e, ".")) {
+				dirs = append(dirs, e)
+			}
+		}
+		if len(dirs) == 1 {
+			dirs = append(dirs, dirs[0]+" ")
+		}
+		Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, dirs)
+		return dirs
 	case OptionsNode:
 		if strings.HasPrefix(prefix, "-") {
 			sortForCompletion(n.Entries)
-			ee := keepByPrefix(n.Entries, prefix)
+			ee := n.keepByPrefix(n.Entries, prefix)
 			Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, ee)
 			return ee
 		}
 	case OptionsWithCompletion:
 		if strings.HasPrefix(prefix, "-") {
 			sortForCompletion(n.Entries)
-			ee := keepByPrefix(n.Entries, prefix)
+			ee := n.keepByPrefix(n.Entries, prefix)
 			Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, ee)
 			return ee
 		}
 	case CustomNode:
 		sortForCompletion(n.Entries)
-		ee := keepByPrefix(n.Entries, prefix)
+		ee := n.keepByPrefix(n.Entries, prefix)
 		Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, ee)
 		return ee
 	}
@@ -133,6 +192,22 @@ func (n *Node) SelfCompletions(prefix string) []string {
 
 // Completions -
 func (n *Node) Completions(prefix string) []string {
+	return n.completions(prefix, 0)
+}
+
+// positional - Returns the completion node for the positional argument at the given index.
+func (n *Node) positional(argIndex int) *Node {
+	if argIndex < len(n.Positionals) {
+		return n.Positionals[argIndex]
+	}
+	if n.Variadic && len(n.Positionals) > 0 {
+		return n.Positionals[len(n.Positionals)-1]
+	}
+	return nil
+}
+
+// completions - Same as Completions but includes the completions of the positional argument at the given index.
+func (n *Node) completions(prefix string, argIndex int) []string {
 	results := []string{}
 	stringNodeResults := []string{}
 	optionResults := []string{}
@@ -146,6 +221,9 @@ func (n *Node) Completions(prefix string) []string {
 			results = append(results, child.SelfCompletions(prefix)...)
 		}
 	}
+	if p := n.positional(argIndex); p != nil && !strings.HasPrefix(prefix, "-") {
+		results = append(results, p.SelfCompletions(prefix)...)
+	}
 	sortForCompletion(results)
 	sortForCompletion(optionResults)
 	// Put command completions first, then options, then anything else
@@ -176,10 +254,10 @@ func (n *Node) GetChildrenByKind(kind kind) []*Node {
 }
 
 // keepByPrefix - Given a list and a prefix filter, it returns a list subset of the elements that start with the prefix.
-func keepByPrefix(list []string, prefix string) []string {
+func (n *Node) keepByPrefix(list []string, prefix string) []string {
 	keepList := []string{}
 	for _, e := range list {
-		if strings.HasPrefix(e, prefix) {
+		if n.hasPrefix(e, prefix) {
 			keepList = append(keepList, e)
 		}
 	}
@@ -199,6 +277,11 @@ func discardByPrefix(list []string, prefix string) []string {
 
 // CompLineComplete - Given a compLine (get it with os.Getenv("COMP_LINE")) it returns a list of completions.
 func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
+	return n.compLineComplete(lastWasOption, 0, compLine)
+}
+
+// compLineComplete - argIndex is the index of the positional argument being completed.
+func (n *Node) compLineComplete(lastWasOption bool, argIndex int, compLine string) []string {
 	// TODO: This split might not consider files that have spaces in them.
 	re := regexp.MustCompile(`\s+`)
 	compLineParts := re.Split(compLine, -1)
@@ -216,31 +299,39 @@ func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
 	if len(compLineParts) >= 1 {
 		current := compLineParts[0]
 
-		cc := n.Completions(current)
+		// The word after an option that takes an argument is its value,
+		// skip it so it isn't matched as a command or counted as a positional argument.
+		if lastWasOption && len(compLineParts) > 1 {
+			Debug.Printf("CompLineComplete - node: %s, compLine %s - Option value %s, recursing to self\n", n.Name, compLine, current)
+			return n.compLineComplete(false, argIndex, strings.Join(compLineParts, " "))
+		}
+
+		cc := n.completions(current, argIndex)
 		if len(compLineParts) == 1 && len(cc) > 1 {
 			Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Multiple completions for this compLine\n", n.Name, compLine, cc)
 			return cc
 		}
 		// Check if the current fully matches a command (child node)
-		child := n.GetChildByName(current)
-		if child.Kind == CommandNode && child.Name == current {
-			Debug.Printf("CompLineComplete - node: %s, compLine %s - Recursing into command %s\n", n.Name, compLine, current)
-			// Recurse into the child node's completion
-			return child.CompLineComplete(false, strings.Join(compLineParts, " "))
+		for _, child := range n.GetChildrenByKind(CommandNode) {
+			if n.equal(child.Name, current) {
+				Debug.Printf("CompLineComplete - node: %s, compLine %s - Recursing into command %s\n", n.Name, compLine, current)
+				// Recurse into the child node's completion
+				return child.CompLineComplete(false, strings.Join(compLineParts, " "))
+			}
 		}
 		// Check if the current fully matches an option
 		list := n.GetChildrenByKind(OptionsNode)
 		list = append(list, n.GetChildrenByKind(CustomNode)...)
 		for _, child := range list {
 			for _, e := range child.Entries {
-				if current == e {
+				if n.equal(current, e) {
 					if len(compLineParts) == 1 {
 						Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Fully Matched Option/Custom\n", n.Name, compLine, current)
 						return []string{current}
 					}
 					Debug.Printf("CompLineComplete - node: %s, compLine %s - Fully matched Option/Custom %s, recursing to self\n", n.Name, compLine, current)
 					// Recurse into the node self completion
-					return n.CompLineComplete(false, strings.Join(compLineParts, " "))
+					return n.compLineComplete(false, argIndex, strings.Join(compLineParts, " "))
 				}
 			}
 		}
@@ -249,23 +340,28 @@ func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
 		list = append(list, n.GetChildrenByKind(CustomNode)...)
 		for _, child := range list {
 			for _, e := range child.Entries {
-				if current == e {
+				if n.equal(current, e) {
 					if len(compLineParts) == 1 {
 						Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Fully Matched Option/Custom\n", n.Name, compLine, current)
 						return []string{current}
 					}
 					Debug.Printf("CompLineComplete - node: %s, compLine %s - Fully matched Option/Custom %s, recursing to self\n", n.Name, compLine, current)
 					// Recurse into the node self completion
-					return n.CompLineComplete(true, strings.Join(compLineParts, " "))
+					return n.compLineComplete(true, argIndex, strings.Join(compLineParts, " "))
 				}
-				if strings.HasPrefix(current, e+"=") {
+				if n.hasPrefix(current, e+"=") {
 					if len(compLineParts) == 1 {
+						valuesNode := child.GetChildByName(e)
+						if valuesNode.Kind == CustomNode && valuesNode.Name == e {
+							Debug.Printf("CompLineComplete - node: %s, compLine %s - Option values for %s\n", n.Name, compLine, e)
+							return valuesNode.SelfCompletions(current[len(e)+1:])
+						}
 						Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Fully Matched Option/Custom with =\n", n.Name, compLine, current)
-						return n.Completions(current)
+						return n.completions(current, argIndex)
 					}
 					Debug.Printf("CompLineComplete - node: %s, compLine %s - Fully matched Option/Custom  with = %s, recursing to self\n", n.Name, compLine, current)
 					// Recurse into the node self completion
-					return n.CompLineComplete(false, strings.Join(compLineParts, " "))
+					return n.compLineComplete(false, argIndex, strings.Join(compLineParts, " "))
 				}
 			}
 		}
@@ -280,26 +376,69 @@ func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
 					}
 					Debug.Printf("CompLineComplete - node: %s, compLine %s - Fully matched File %s, recursing to self\n", n.Name, compLine, current)
 					// Recurse into the node self completion
-					return n.CompLineComplete(false, strings.Join(compLineParts, " "))
+					return n.compLineComplete(false, argIndex, strings.Join(compLineParts, " "))
 				}
 			}
 		}
 
 		// Doesn't match anything but previous arg was an option
 		if lastWasOption {
-			Debug.Printf("CompLineComplete - node: %s, compLine %s - Previous was option %s, recursing to self\n", n.Name, compLine, current)
-			if len(compLineParts) == 1 {
-				return []string{current}
-			}
-			return n.CompLineComplete(false, strings.Join(compLineParts, " "))
+			Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Previous was option\n", n.Name, compLine, current)
+			return []string{current}
+		}
+
+		// A positional argument was completed, continue with the next one
+		if len(n.Positionals) > 0 && len(compLineParts) > 1 && !strings.HasPrefix(current, "-") {
+			Debug.Printf("CompLineComplete - node: %s, compLine %s - Positional argument %d %s, recursing to self\n", n.Name, compLine, argIndex, current)
+			return n.compLineComplete(false, argIndex+1, strings.Join(compLineParts, " "))
 		}
 
 		// Return a partial match
 		Debug.Printf("CompLineComplete - node: %s, compLine %s - Partial match %s\n", n.Name, compLine, current)
-		return n.Completions(current)
+		return n.completions(current, argIndex)
 	}
 
 	Debug.Printf("CompLineComplete - node: %s, compLine %s > [] - Return all results\n", n.Name, compLine)
 	// No partial request, return all results
-	return n.Completions("")
+	return n.completions("", argIndex)
+}
+
+// commandNode - Returns the command node the last word of the compLine is completed in.
+func (n *Node) commandNode(compLine string) *Node {
+	parts := regexp.MustCompile(`\s+`).Split(compLine, -1)
+	node := n
+	for i := 1; i < len(parts)-1; i++ {
+		for _, child := range node.GetChildrenByKind(CommandNode) {
+			if node.equal(child.Name, parts[i]) {
+				node = child
+				break
+			}
+		}
+	}
+	return node
+}
+
+// description - Returns the description of the completion in the node.
+func (n *Node) description(c string) string {
+	if child := n.GetChildByName(c); child.Kind == CommandNode {
+		return child.Description
+	}
+	return n.Descriptions[c]
+}
+
+// DescribedCompletions - Same as CompLineComplete but each completion is paired with its description, the description can be empty.
+// Entries that only differ by trailing spaces, used to prevent bash from adding a space after a directory, are merged.
+func (n *Node) DescribedCompletions(compLine string) [][2]string {
+	node := n.commandNode(compLine)
+	results := [][2]string{}
+	seen := map[string]bool{}
+	for _, c := range n.CompLineComplete(false, compLine) {
+		c = strings.TrimRight(c, " ")
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		results = append(results, [2]string{c, strings.Join(strings.Fields(node.description(c)), " ")})
+	}
+	return results
 }
@@ -28,7 +28,9 @@ func TestGetChildNames(t *testing.T) {
 	// Tree setup
 	rootNode := NewNode("executable", Root, nil)
 	rootNode.AddChild(NewNode("options", OptionsNode, []string{"--version", "--help", "-v", "-h"}))
-	rootNode.AddChild(NewNode("options", OptionsWithCompletion, []string{"--profile", "-p"}))
+	optionsWithCompletion := NewNode("options", OptionsWithCompletion, []string{"--profile", "-p", "--format"})
+	optionsWithCompletion.AddChild(NewNode("--format", CustomNode, []string{"json", "yaml", "table"}))
+	rootNode.AddChild(optionsWithCompletion)
 
 	logNode := NewNode("log", CommandNode, nil)
 	rootNode.AddChild(logNode)
@@ -47,6 +49,23 @@ func TestGetChildNames(t *testing.T) {
 	logNode.AddChild(NewNode("options", OptionsNode, []string{"--help"}))
 	logNode.AddChild(NewNode("test/test_tree", FileListNode, nil))
 
+	// Positional arguments tree setup
+	argsRootNode := NewNode("executable", Root, nil)
+	cpNode := NewNode("cp", CommandNode, nil)
+	cpNode.AddChild(NewNode("options", OptionsNode, []string{"--force"}))
+	cpOptionsWithArg := NewNode("options-with-arg", OptionsWithCompletion, []string{"--format", "--mode"})
+	cpOptionsWithArg.AddChild(NewNode("--format", CustomNode, []string{"json", "yaml"}))
+	cpNode.AddChild(cpOptionsWithArg)
+	cpNode.Positionals = []*Node{
+		NewNode("src", CustomNode, []string{"a1", "a2", "b1"}),
+		NewNode("test/test_tree", DirListNode, nil),
+	}
+	argsRootNode.AddChild(cpNode)
+	rmNode := NewNode("rm", CommandNode, nil)
+	rmNode.Positionals = []*Node{NewNode("test/test_tree", FileListNode, nil)}
+	rmNode.Variadic = true
+	argsRootNode.AddChild(rmNode)
+
 	// Test Raw Completions
 	tests := []struct {
 		name    string
@@ -57,7 +76,7 @@ func TestGetChildNames(t *testing.T) {
 		{"get commands", rootNode, "", []string{"log", "logger", "show"}},
 		{"get commands", rootNode, "log", []string{"log", "logger"}},
 		{"get commands", rootNode, "show", []string{"show"}},
-		{"get options", rootNode, "-", []string{"-h", "--help", "-p", "--profile", "-v", "--version"}},
+		{"get options", rootNode, "-", []string{"--format", "-h", "--help", "-p", "--profile", "-v", "--version"}},
 		{"get options", rootNode, "-h", []string{"-h"}},
 		{"get commands", rootNode.GetChildByName("x"), "", []string{}},
 		{"filter out hidden files", rootNode.GetChildByName("log"), "", []string{"sublog", "aFile1", "aFile2", "bDir1/", "bDir2/", "cFile1", "cFile2"}},
@@ -103,7 +122,7 @@ func TestGetChildNames(t *testing.T) {
 		{"top level", rootNode, "./executable log", []string{"log", "logger"}},
 		{"top level", rootNode, "./executable  log", []string{"log", "logger"}},
 		{"top level", rootNode, "./executable sh", []string{"show"}},
-		{"options", rootNode, "./executable -", []string{"-h", "--help", "-p", "--profile", "-v", "--version"}},
+		{"options", rootNode, "./executable -", []string{"--format", "-h", "--help", "-p", "--profile", "-v", "--version"}},
 		{"options", rootNode, "./executable -h", []string{"-h"}},
 		{"options", rootNode, "./executable -h ", []string{"log", "logger", "show"}},
 		{"options", rootNode, "./executable  -h  l", []string{"log", "logger"}},
@@ -116,6 +135,10 @@ func TestGetChildNames(t *testing.T) {
 		{"options", rootNode, "./executable  --profile=dev", []string{}},
 		{"options", rootNode, "./executable  --profile dev", []string{"dev"}},
 		{"options", rootNode, "./executable  --profile dev  l", []string{"log", "logger"}},
+		{"option values", rootNode, "./executable --format=", []string{"json", "table", "yaml"}},
+		{"option values", rootNode, "./executable --format=t", []string{"table"}},
+		{"option values", rootNode, "./executable --format=x", []string{}},
+		{"option values", rootNode, "./executable --format=json l", []string{"log", "logger"}},
 		{"command", rootNode, "./executable log ", []string{"sublog", "aFile1", "aFile2", "bDir1/", "bDir2/", "cFile1", "cFile2"}},
 		{"command", rootNode, "./executable log bDir1/f", []string{"bDir1/file"}},
 		{"command", rootNode, "./executable log bDir1/file ", []string{"sublog", "aFile1", "aFile2", "bDir1/", "bDir2/", "cFile1", "cFile2"}},
@@ -125,6 +148,24 @@ func TestGetChildNames(t *testing.T) {
 		{"command", rootNode, "./executable logger ../.aFile2", []string{"../.aFile2"}},
 		{"command", rootNode, "./executable show", []string{"abcd1234", "bbcd/1234", "..hola", "--hola"}},
 		{"not a valid arg", rootNode, "./executable dev", []string{}},
+		{"positional", argsRootNode, "./executable cp ", []string{"a1", "a2", "b1"}},
+		{"positional", argsRootNode, "./executable cp a", []string{"a1", "a2"}},
+		{"positional", argsRootNode, "./executable cp -", []string{"--force", "--format", "--mode"}},
+		{"positional", argsRootNode, "./executable cp a1 ", []string{"bDir1/", "bDir2/"}},
+		{"positional", argsRootNode, "./executable cp a1 b", []string{"bDir1/", "bDir2/"}},
+		{"positional", argsRootNode, "./executable cp a1 bDir1", []string{"bDir1/ ", "bDir1/"}},
+		{"positional", argsRootNode, "./executable cp a1 .", []string{"./", "../"}},
+		{"positional", argsRootNode, "./executable cp --force a1 ", []string{"bDir1/", "bDir2/"}},
+		{"positional", argsRootNode, "./executable cp a1 bDir1/ ", []string{}},
+		{"positional after option value", argsRootNode, "./executable cp --format json ", []string{"a1", "a2", "b1"}},
+		{"positional after option value", argsRootNode, "./executable cp --format json a1 ", []string{"bDir1/", "bDir2/"}},
+		{"positional after option value", argsRootNode, "./executable cp --mode 644 a1 b", []string{"bDir1/", "bDir2/"}},
+		{"positional after option value", argsRootNode, "./executable cp a1 --format=json b", []string{"bDir1/", "bDir2/"}},
+		{"option value", rootNode, "./executable --profile log ", []string{"log", "logger", "show"}},
+		{"option value", rootNode, "./executable --profile show l", []string{"log", "logger"}},
+		{"option value", argsRootNode, "./executable cp --mode a1 ", []string{"a1", "a2", "b1"}},
+		{"positional", argsRootNode, "./executable rm aFile1 c", []string{"cFile1", "cFile2"}},
+		{"positional", argsRootNode, "./executable rm aFile1 cFile1 a", []string{"aFile1", "aFile2"}},
 	}
 	for _, tt := range compLineTests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -137,3 +178,44 @@ func TestGetChildNames(t *testing.T) {
 		})
 	}
 }
+
+func TestIgnoreCase(t *testing.T) {
+	rootNode := NewNode("executable", Root, nil)
+	rootNode.AddChild(NewNode("options", OptionsNode, []string{"--help", "--Verbose"}))
+	optionsWithCompletion := NewNode("options", OptionsWithCompletion, []string{"--format"})
+	optionsWithCompletion.AddChild(NewNode("--format", CustomNode, []string{"json", "yaml"}))
+	rootNode.AddChild(optionsWithCompletion)
+	logNode := NewNode("Log", CommandNode, nil)
+	logNode.AddChild(NewNode("options", OptionsNode, []string{"--follow"}))
+	logNode.Positionals = []*Node{NewNode("level", CustomNode, []string{"Debug", "info"})}
+	rootNode.AddChild(logNode)
+
+	tests := []struct {
+		name       string
+		ignoreCase bool
+		compLine   string
+		results    []string
+	}{
+		{"command", false, "./executable l", []string{}},
+		{"command", true, "./executable l", []string{"Log"}},
+		{"command", true, "./executable LOG -", []string{"--follow"}},
+		{"options", false, "./executable --v", []string{}},
+		{"options", true, "./executable --v", []string{"--Verbose"}},
+		{"options", true, "./executable --HELP", []string{"--HELP"}},
+		{"options", true, "./executable --HELP l", []string{"Log"}},
+		{"option values", true, "./executable --FORMAT=J", []string{"json"}},
+		{"option values", true, "./executable --FORMAT json l", []string{"Log"}},
+		{"positional", true, "./executable log d", []string{"Debug"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := setupLogging()
+			rootNode.SetIgnoreCase(tt.ignoreCase)
+			got := rootNode.CompLineComplete(false, tt.compLine)
+			if !reflect.DeepEqual(got, tt.results) {
+				t.Errorf("CompLineComplete() got = '%#v', want '%#v'", got, tt.results)
+			}
+			t.Log(buf.String())
+		})
+	}
+}
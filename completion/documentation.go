@@ -36,5 +36,9 @@ However, there file and dir completions are provided as a convenience.
 
 Custom completions for options are triggered with the `=` sing after the full option test has been provided.
 
+Completions are written one per line for bash.
+For zsh and fish, ZshCompLineComplete and FishCompLineComplete pair each completion with the Description of the CommandNode or the entry in the Descriptions of the node,
+and ZshScript and FishScript generate the scripts that call the program.
+
 */
 package completion
@@ -0,0 +1,57 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FishCompLineComplete - Given a compLine it returns a list of completions in the fish format: `completion<TAB>description`.
+// Option argument completions, `--format=json`, include the option since fish doesn't split the word at the `=`.
+func (n *Node) FishCompLineComplete(compLine string) []string {
+	parts := regexp.MustCompile(`\s+`).Split(compLine, -1)
+	current := parts[len(parts)-1]
+	prefix := ""
+	if i := strings.Index(current, "="); i > 0 && strings.HasPrefix(current, "-") {
+		prefix = current[:i+1]
+	}
+	results := []string{}
+	for _, c := range n.DescribedCompletions(compLine) {
+		line := c[0]
+		if !strings.HasPrefix(line, prefix) {
+			line = prefix + line
+		}
+		if c[1] != "" {
+			line += "\t" + c[1]
+		}
+		results = append(results, line)
+	}
+	return results
+}
+
+// FishScript - Returns the fish completion script for the program.
+// The script calls the program with COMP_LINE and the shellEnvVar environment variable set to fish,
+// the program is expected to write the FishCompLineComplete results.
+// The program completes files and directories itself, so fish's own file completion is disabled.
+//
+// Save it as `name.fish` in the `~/.config/fish/completions` directory or source it.
+func FishScript(name, shellEnvVar string) string {
+	return fmt.Sprintf(`# fish completion for %[1]s
+
+function __%[2]s_complete
+	set -l cmd (commandline -opc)[1]
+	set -l line (commandline -cp)
+	env %[4]s=fish "COMP_LINE=$line" $cmd 2>/dev/null
+end
+
+complete -c %[3]s -f -a '(__%[2]s_complete)'
+`, name, shellFuncName(name), shellQuote(name), shellEnvVar)
+}
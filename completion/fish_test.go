@@ -0,0 +1,81 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFishCompLineComplete(t *testing.T) {
+	rootNode := NewNode("executable", Root, nil)
+	rootNode.AddChild(NewNode("options", OptionsNode, []string{"--help", "--version"}))
+	rootNode.Descriptions["--help"] = "Show\nhelp"
+	optionsWithCompletion := NewNode("options-with-arg", OptionsWithCompletion, []string{"--format"})
+	optionsWithCompletion.AddChild(NewNode("--format", CustomNode, []string{"json", "yaml"}))
+	rootNode.AddChild(optionsWithCompletion)
+
+	logNode := NewNode("log", CommandNode, nil)
+	logNode.Description = "Show commit logs"
+	logNode.AddChild(NewNode("test/test_tree", FileListNode, nil))
+	rootNode.AddChild(logNode)
+
+	tests := []struct {
+		name     string
+		compLine string
+		expected []string
+	}{
+		{"commands", "executable ", []string{"log\tShow commit logs"}},
+		{"options", "executable --", []string{"--format", "--help\tShow help", "--version"}},
+		{"option values", "executable --format=", []string{"--format=json", "--format=yaml"}},
+		{"option values partial", "executable --format=j", []string{"--format=json"}},
+		{"files", "executable log bDir1/", []string{"bDir1/file", "bDir1/.file"}},
+		// fish file completion is disabled in the script, files and directories come from the program
+		{"files and dirs", "executable log ", []string{"aFile1", "aFile2", "bDir1/", "bDir2/", "cFile1", "cFile2"}},
+		{"dirs", "executable log b", []string{"bDir1/", "bDir2/"}},
+		{"empty", "", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rootNode.FishCompLineComplete(tt.compLine)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got = '%#v', want '%#v'", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFishScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		program  string
+		expected []string
+	}{
+		{"plain", "mygit", []string{
+			"function __mygit_complete\n",
+			`env COMPLETION_SHELL=fish "COMP_LINE=$line" $cmd`,
+			"complete -c mygit -f -a '(__mygit_complete)'\n",
+		}},
+		{"quoted", "my git.v2", []string{
+			"function __my_git_v2_complete\n",
+			"complete -c 'my git.v2' -f -a '(__my_git_v2_complete)'\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := FishScript(tt.program, "COMPLETION_SHELL")
+			for _, s := range tt.expected {
+				if !strings.Contains(script, s) {
+					t.Errorf("Script doesn't contain %q:\n%s", s, script)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ZshCompLineComplete - Given a compLine it returns a list of completions in the zsh `_describe` format: `completion:description`.
+// Colons in the completion are escaped.
+func (n *Node) ZshCompLineComplete(compLine string) []string {
+	results := []string{}
+	for _, c := range n.DescribedCompletions(compLine) {
+		line := strings.ReplaceAll(c[0], ":", `\:`)
+		if c[1] != "" {
+			line += ":" + c[1]
+		}
+		results = append(results, line)
+	}
+	return results
+}
+
+// ZshScript - Returns the zsh completion script for the program.
+// The script calls the program with COMP_LINE and the shellEnvVar environment variable set to zsh,
+// the program is expected to write the ZshCompLineComplete results.
+//
+// Save it as `_name` in a directory in the zsh `fpath` or source it.
+func ZshScript(name, shellEnvVar string) string {
+	return fmt.Sprintf(`#compdef %[1]s
+compdef _%[2]s %[3]s
+
+# zsh completion for %[1]s
+
+_%[2]s() {
+	local -a candidates dirs
+	local line
+	if [[ $PREFIX == -*=* ]]; then
+		compset -P '*='
+	fi
+	for line in "${(@f)$(%[4]s=zsh COMP_LINE="${words[1,CURRENT]}" "${words[1]}" 2>/dev/null)}"; do
+		if [[ -z $line ]]; then
+			continue
+		elif [[ $line == */ ]]; then
+			dirs+=("$line")
+		else
+			candidates+=("$line")
+		fi
+	done
+	if (( ${#dirs} )); then
+		compadd -S '' -- "${dirs[@]}"
+	fi
+	if (( ${#candidates} )); then
+		_describe -t values '' candidates
+	fi
+	return 0
+}
+
+# Don't run the completion function when being sourced
+if [[ $funcstack[1] == _%[2]s ]]; then
+	_%[2]s "$@"
+fi
+`, name, shellFuncName(name), shellQuote(name), shellEnvVar)
+}
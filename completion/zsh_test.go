@@ -0,0 +1,89 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestZshCompLineComplete(t *testing.T) {
+	rootNode := NewNode("executable", Root, nil)
+	rootNode.AddChild(NewNode("options", OptionsNode, []string{"--help", "-h", "--version"}))
+	rootNode.Descriptions["--help"] = "Show help"
+	rootNode.Descriptions["-h"] = "Show help"
+
+	logNode := NewNode("log", CommandNode, nil)
+	logNode.Description = "Show commit\nlogs"
+	logNode.AddChild(NewNode("options", OptionsNode, []string{"--oneline"}))
+	logNode.Descriptions["--oneline"] = "One line per commit"
+	rootNode.AddChild(logNode)
+
+	sublogNode := NewNode("sublog", CommandNode, nil)
+	sublogNode.AddChild(NewNode("custom", CustomNode, []string{"a:b", "log"}))
+	sublogNode.Descriptions["log"] = "entry named as a command"
+	logNode.AddChild(sublogNode)
+
+	cpNode := NewNode("cp", CommandNode, nil)
+	cpNode.Positionals = []*Node{NewNode("test/test_tree", DirListNode, nil)}
+	rootNode.AddChild(cpNode)
+
+	tests := []struct {
+		name     string
+		compLine string
+		expected []string
+	}{
+		{"commands", "executable ", []string{"log:Show commit logs", "cp"}},
+		{"options", "executable --", []string{"--help:Show help", "--version"}},
+		{"command options", "executable log --", []string{"--oneline:One line per commit"}},
+		{"nested command", "executable log sublog ", []string{`a\:b`, "log:entry named as a command"}},
+		{"dirs", "executable cp b", []string{"bDir1/", "bDir2/"}},
+		{"single dir merged", "executable cp bDir1", []string{"bDir1/"}},
+		{"empty", "", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rootNode.ZshCompLineComplete(tt.compLine)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got = '%#v', want '%#v'", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestZshScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		program  string
+		expected []string
+	}{
+		{"plain", "mygit", []string{
+			"#compdef mygit\ncompdef _mygit mygit\n",
+			"\n_mygit() {\n",
+			`COMPLETION_SHELL=zsh COMP_LINE="${words[1,CURRENT]}" "${words[1]}"`,
+			"if [[ $funcstack[1] == _mygit ]]; then\n\t_mygit \"$@\"\nfi\n",
+		}},
+		{"quoted", "my git.v2", []string{
+			"compdef _my_git_v2 'my git.v2'\n",
+			"\n_my_git_v2() {\n",
+			"if [[ $funcstack[1] == _my_git_v2 ]]; then\n\t_my_git_v2 \"$@\"\nfi\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := ZshScript(tt.program, "COMPLETION_SHELL")
+			for _, s := range tt.expected {
+				if !strings.Contains(script, s) {
+					t.Errorf("Script doesn't contain %q:\n%s", s, script)
+				}
+			}
+		})
+	}
+}
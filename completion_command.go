@@ -0,0 +1,68 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/completion"
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// completionShells - Shells supported by CompletionCommand.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionProgram - Returns the program name and the path bash uses to call it.
+// Programs invoked with a relative path are called with their absolute path so the completion works from any directory.
+func completionProgram() (string, string) {
+	name := filepath.Base(os.Args[0])
+	path := os.Args[0]
+	if strings.ContainsRune(path, filepath.Separator) {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+	}
+	return name, path
+}
+
+// CompletionCommand - Adds a command that writes the completion script of the program for the shell given as argument to stdout.
+// The supported shells are bash, zsh and fish.
+// The command help describes how to install the script.
+//
+//     opt.CompletionCommand("completion")
+//
+// Called as `prog completion bash`.
+func (gopt *GetOpt) CompletionCommand(name string) *GetOpt {
+	program, path := completionProgram()
+	cmd := gopt.NewCommand(name, text.CompletionCommandDescription)
+	cmd.Arg("shell", option.StringType, true, cmd.CompleteList(completionShells...),
+		cmd.Description(fmt.Sprintf(text.CompletionShellDescription, program, name)))
+	cmd.SetCommandFn(func(ctx context.Context, c *GetOpt, args []string) error {
+		shell := c.ArgValue("shell").(string)
+		script := ""
+		switch shell {
+		case "bash":
+			script = completion.BashScript(program, path)
+		case "zsh":
+			script = completion.ZshScript(program, CompletionShellEnvVar)
+		case "fish":
+			script = completion.FishScript(program, CompletionShellEnvVar)
+		default:
+			return fmt.Errorf(text.ErrorArgumentNotValidValue, "shell", shell, completionShells)
+		}
+		fmt.Fprint(completionWriter, script)
+		return nil
+	})
+	return cmd
+}
@@ -0,0 +1,83 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DavidGamba/go-getoptions/completion"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+func TestCompletionCommand(t *testing.T) {
+	defer func(arg0 string) { os.Args[0] = arg0 }(os.Args[0])
+	defer func() { completionWriter = os.Stdout }()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		arg0     string
+		args     []string
+		expected string
+		err      string
+	}{
+		{"bash", "prog", []string{"completion", "bash"}, completion.BashScript("prog", "prog"), ""},
+		{"bash relative", "./bin/prog", []string{"completion", "bash"}, completion.BashScript("prog", filepath.Join(wd, "bin", "prog")), ""},
+		{"zsh", "./bin/prog", []string{"completion", "zsh"}, completion.ZshScript("prog", CompletionShellEnvVar), ""},
+		{"fish", "prog", []string{"completion", "fish"}, completion.FishScript("prog", CompletionShellEnvVar), ""},
+		{"unknown", "prog", []string{"completion", "tcsh"}, "",
+			fmt.Sprintf(text.ErrorArgumentNotValidValue, "shell", "tcsh", []string{"bash", "zsh", "fish"})},
+		{"missing", "prog", []string{"completion"}, "", fmt.Sprintf(text.ErrorMissingRequiredArgument, "shell")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Args[0] = tt.arg0
+			buf := new(bytes.Buffer)
+			completionWriter = buf
+			opt := New()
+			opt.CompletionCommand("completion")
+			err := opt.Dispatch(context.Background(), "help", tt.args)
+			if tt.err == "" && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Unexpected script:\n%s", buf.String())
+			}
+		})
+	}
+
+	t.Run("help", func(t *testing.T) {
+		os.Args[0] = "prog"
+		opt := New()
+		cmd := opt.CompletionCommand("completion")
+		h := cmd.Help()
+		for _, s := range []string{
+			"prog completion - " + text.CompletionCommandDescription,
+			"prog completion <shell>",
+			"bash: add 'source <(prog completion bash)' to ~/.bashrc",
+			"fish: prog completion fish > ~/.config/fish/completions/prog.fish",
+		} {
+			if !strings.Contains(h, s) {
+				t.Errorf("Help doesn't contain %q:\n%s", s, h)
+			}
+		}
+	})
+}
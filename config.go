@@ -0,0 +1,305 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// ConfigFormat - Format of the configuration file.
+type ConfigFormat int
+
+// Configuration file formats
+const (
+	JSONFormat ConfigFormat = iota
+	INIFormat
+)
+
+// SetConfigFile - Reads the option values from the configuration file at the given path.
+// The format is chosen from the file extension: `.json` for JSON and `.ini`, `.cfg` or `.conf` for INI.
+// See LoadConfig.
+func (gopt *GetOpt) SetConfigFile(path string) error {
+	var format ConfigFormat
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = JSONFormat
+	case ".ini", ".cfg", ".conf":
+		format = INIFormat
+	default:
+		return fmt.Errorf(text.ErrorConfigParse, path, "unknown file extension")
+	}
+	fh, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	return gopt.loadConfig(fh, format, path)
+}
+
+// LoadConfig - Reads the option values from the given configuration.
+// Precedence higher to lower: CLI option, environment variable, configuration, option default.
+//
+// Keys are option names or aliases.
+// Commands are objects in JSON and sections in INI, `[deploy]` or `[deploy.sub]` for nested commands.
+// For example:
+//
+//     {"profile": "dev", "tag": ["a", "b"], "label": {"k": "v"}, "deploy": {"region": "us-west-2"}}
+//
+//     profile = dev
+//     tag = a
+//     tag = b
+//     label = k=v
+//     [deploy]
+//     region = us-west-2
+//
+// When a value is read from the configuration, opt.Called(name) returns true and opt.CalledAs(name) returns
+// the configuration source and key, for example: `config.json:deploy.region`.
+// The command line replaces the configuration values of slice and map options.
+//
+// Unknown keys are ignored by default, use SetConfigUnknownMode to change this behaviour.
+//
+// NOTE: Call after the options and commands have been defined and before Parse.
+func (gopt *GetOpt) LoadConfig(r io.Reader, format ConfigFormat) error {
+	return gopt.loadConfig(r, format, "config")
+}
+
+// SetConfigUnknownMode - Determines how to behave when finding an unknown key in a configuration file.
+// By default unknown keys are ignored (Pass).
+// Warn writes a warning to gopt.Writer and Fail returns an error.
+func (gopt *GetOpt) SetConfigUnknownMode(mode UnknownMode) *GetOpt {
+	gopt.configUnknownMode = mode
+	return gopt
+}
+
+func (gopt *GetOpt) loadConfig(r io.Reader, format ConfigFormat, source string) error {
+	switch format {
+	case JSONFormat:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		m := map[string]interface{}{}
+		err := dec.Decode(&m)
+		if err != nil {
+			return fmt.Errorf(text.ErrorConfigParse, source, err)
+		}
+		return gopt.applyJSONConfig(gopt, m, source, "")
+	default:
+		return gopt.applyINIConfig(r, source)
+	}
+}
+
+// applyJSONConfig - loader is the GetOpt object used to load the configuration,
+// its SetConfigUnknownMode applies to the commands as well.
+func (gopt *GetOpt) applyJSONConfig(loader *GetOpt, m map[string]interface{}, source, prefix string) error {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := prefix + k
+		if cmd, ok := gopt.commands[k]; ok {
+			if sub, ok := m[k].(map[string]interface{}); ok {
+				err := cmd.applyJSONConfig(loader, sub, source, key+".")
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf(text.ErrorConfigInvalidValue, key, source)
+		}
+		opt := gopt.configOption(k)
+		if opt == nil {
+			err := loader.configUnknownKey(key, source)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		values, ok := jsonConfigValues(m[k], opt.OptType == option.StringMapType)
+		if !ok {
+			return fmt.Errorf(text.ErrorConfigInvalidValue, key, source)
+		}
+		err := gopt.setFromConfig(opt, source, key, values)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonConfigValues - Converts a JSON value to the list of arguments passed to the option.
+func jsonConfigValues(v interface{}, isMap bool) ([]string, bool) {
+	switch v := v.(type) {
+	case string:
+		return []string{v}, true
+	case json.Number:
+		return []string{v.String()}, true
+	case bool:
+		return []string{fmt.Sprintf("%t", v)}, true
+	case []interface{}:
+		values := []string{}
+		for _, e := range v {
+			if _, ok := e.([]interface{}); ok {
+				return nil, false
+			}
+			ee, ok := jsonConfigValues(e, false)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, ee...)
+		}
+		return values, true
+	case map[string]interface{}:
+		if !isMap {
+			return nil, false
+		}
+		values := []string{}
+		for k, e := range v {
+			ee, ok := jsonConfigValues(e, false)
+			if !ok || len(ee) != 1 {
+				return nil, false
+			}
+			values = append(values, k+"="+ee[0])
+		}
+		sort.Strings(values)
+		return values, true
+	}
+	return nil, false
+}
+
+func (gopt *GetOpt) applyINIConfig(r io.Reader, source string) error {
+	scanner := bufio.NewScanner(r)
+	cmd := gopt
+	section := ""
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			cmd = gopt
+			for _, name := range strings.Split(section, ".") {
+				if cmd != nil {
+					cmd = cmd.commands[name]
+				}
+			}
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return fmt.Errorf(text.ErrorConfigParse, source, fmt.Sprintf("line %d: expected 'key = value'", n))
+		}
+		k := strings.TrimSpace(line[:i])
+		value := strings.TrimSpace(line[i+1:])
+		if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+			value = value[1 : len(value)-1]
+		}
+		key := k
+		if section != "" {
+			key = section + "." + k
+		}
+		var opt *option.Option
+		if cmd != nil {
+			opt = cmd.configOption(k)
+		}
+		if opt == nil {
+			err := gopt.configUnknownKey(key, source)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := cmd.setFromConfig(opt, source, key, []string{value})
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// configOption - Returns the option that matches the key by name or alias.
+func (gopt *GetOpt) configOption(key string) *option.Option {
+	for _, opt := range gopt.obj {
+		for _, alias := range opt.Aliases {
+			if alias == key {
+				return opt
+			}
+		}
+	}
+	return nil
+}
+
+func (gopt *GetOpt) configUnknownKey(key, source string) error {
+	switch gopt.configUnknownMode {
+	case Fail:
+		return fmt.Errorf(text.ErrorConfigUnknownKey, key, source)
+	case Warn:
+		fmt.Fprintf(gopt.Writer, "WARNING: "+text.ErrorConfigUnknownKey+"\n", key, source)
+	}
+	return nil
+}
+
+// setFromConfig - Saves the values unless the option was set from an environment variable.
+func (gopt *GetOpt) setFromConfig(opt *option.Option, source, key string, values []string) error {
+	if opt.Called && opt.Source == option.EnvSource {
+		return nil
+	}
+	switch opt.OptType {
+	case option.StringRepeatType, option.IntRepeatType, option.DurationRepeatType, option.StringMapType:
+	case option.ValueType:
+		if opt.MaxArgs == 0 && len(values) != 1 {
+			return fmt.Errorf(text.ErrorConfigInvalidValue, key, source)
+		}
+	case option.BoolType:
+		if len(values) != 1 {
+			return fmt.Errorf(text.ErrorConfigInvalidValue, key, source)
+		}
+		v := strings.ToLower(values[0])
+		if v != "true" && v != "false" {
+			return fmt.Errorf(text.ErrorConfigInvalidValue, key, source)
+		}
+		values = []string{v}
+	default:
+		if len(values) != 1 {
+			return fmt.Errorf(text.ErrorConfigInvalidValue, key, source)
+		}
+	}
+	opt.MapKeysToLower = gopt.mapKeysToLower
+	// Save into a copy so an invalid value leaves the option unchanged.
+	c := opt.Clone()
+	c.SetCalledFrom(option.ConfigSource, source+":"+key)
+	for _, v := range values {
+		err := c.Save(v)
+		if err != nil {
+			return err
+		}
+	}
+	err := c.Validate()
+	if err != nil {
+		return err
+	}
+	err = opt.CopyValue(c)
+	if err == nil {
+		opt.SetCalledFrom(option.ConfigSource, source+":"+key)
+	}
+	return err
+}
@@ -0,0 +1,338 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+type configTest struct {
+	profile string
+	port    int
+	debug   bool
+	timeout time.Duration
+	tags    []string
+	labels  map[string]string
+	region  string
+}
+
+func setupConfigTest() (*GetOpt, *configTest) {
+	c := &configTest{}
+	opt := New()
+	opt.StringVar(&c.profile, "profile", "default", opt.Alias("p"))
+	opt.IntVar(&c.port, "port", 80)
+	opt.BoolVar(&c.debug, "debug", false)
+	opt.DurationVar(&c.timeout, "timeout", 0)
+	opt.StringSliceVar(&c.tags, "tag", 1, 3)
+	opt.StringMapVar(&c.labels, "label", 1, 3)
+	deploy := opt.NewCommand("deploy", "")
+	deploy.StringVar(&c.region, "region", "us-east-1", deploy.Required())
+	deploy.NewCommand("sub", "").String("name", "")
+	return opt, c
+}
+
+func TestLoadConfig(t *testing.T) {
+	expected := &configTest{
+		profile: "dev",
+		port:    8080,
+		debug:   true,
+		timeout: time.Minute,
+		tags:    []string{"a", "b"},
+		labels:  map[string]string{"k": "v", "k2": "v2"},
+		region:  "us-west-2",
+	}
+	tests := []struct {
+		name   string
+		format ConfigFormat
+		input  string
+	}{
+		{"json", JSONFormat, `{
+  "p": "dev", "port": 8080, "debug": true, "timeout": "1m",
+  "tag": ["a", "b"], "label": {"k": "v", "k2": "v2"},
+  "deploy": {"region": "us-west-2", "sub": {"name": "x"}}
+}`},
+		{"ini", INIFormat, `
+# comment
+; comment
+p = "dev"
+port=8080
+debug = TRUE
+timeout = 1m
+tag = a
+tag = b
+label = k=v
+label = k2=v2
+
+[deploy]
+region = us-west-2
+
+[deploy.sub]
+name = x
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, c := setupConfigTest()
+			err := opt.LoadConfig(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			remaining, err := opt.Parse([]string{"deploy"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			_, err = opt.commands["deploy"].Parse(remaining[1:])
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(c, expected) {
+				t.Errorf("Unexpected values:\n%+v\n%+v", c, expected)
+			}
+			if !opt.Called("profile") || opt.CalledAs("profile") != "config:p" {
+				t.Errorf("Unexpected called as: %v, %s", opt.Called("profile"), opt.CalledAs("profile"))
+			}
+			if opt.commands["deploy"].CalledAs("region") != "config:deploy.region" {
+				t.Errorf("Unexpected called as: %s", opt.commands["deploy"].CalledAs("region"))
+			}
+			if opt.commands["deploy"].commands["sub"].Value("name") != "x" {
+				t.Errorf("Unexpected value: %v", opt.commands["deploy"].commands["sub"].Value("name"))
+			}
+		})
+	}
+
+	t.Run("precedence", func(t *testing.T) {
+		os.Setenv("CONFIG_TEST_PORT", "9000")
+		defer os.Unsetenv("CONFIG_TEST_PORT")
+		os.Setenv("CONFIG_TEST_IDS", "1,2")
+		defer os.Unsetenv("CONFIG_TEST_IDS")
+		var profile string
+		var port int
+		opt := New()
+		opt.StringVar(&profile, "profile", "default")
+		opt.IntVar(&port, "port", 80, opt.GetEnv("CONFIG_TEST_PORT"))
+		tags := opt.StringSlice("tag", 1, 3)
+		labels := opt.StringMap("label", 1, 3)
+		other := opt.StringSlice("other", 1, 3)
+		ids := OptSlice[int64](opt, "id", 1, 3, opt.GetEnv("CONFIG_TEST_IDS"))
+		nums := OptSlice[int64](opt, "num", 1, 3)
+		err := opt.LoadConfig(strings.NewReader(`{"profile": "dev", "port": 8080, "tag": ["a", "b"], "label": {"k": "v"}, "other": ["x"], "num": [1, 2]}`), JSONFormat)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		_, err = opt.Parse([]string{"--profile", "prod", "--tag", "c", "--tag", "d", "--label", "k2=v2", "--id", "3", "--num", "3"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if profile != "prod" || opt.CalledAs("profile") != "profile" {
+			t.Errorf("Unexpected value: %s, %s", profile, opt.CalledAs("profile"))
+		}
+		if port != 9000 || opt.CalledAs("port") != "CONFIG_TEST_PORT" {
+			t.Errorf("Unexpected value: %d, %s", port, opt.CalledAs("port"))
+		}
+		if !reflect.DeepEqual(*tags, []string{"c", "d"}) || !reflect.DeepEqual(labels, map[string]string{"k2": "v2"}) {
+			t.Errorf("Unexpected values: %v, %v", *tags, labels)
+		}
+		if !reflect.DeepEqual(*other, []string{"x"}) {
+			t.Errorf("Unexpected values: %v", *other)
+		}
+		if !reflect.DeepEqual(*ids, []int64{3}) || !reflect.DeepEqual(*nums, []int64{3}) {
+			t.Errorf("Unexpected values: %v, %v", *ids, *nums)
+		}
+	})
+
+	t.Run("required", func(t *testing.T) {
+		opt := New()
+		opt.String("profile", "", opt.Required())
+		err := opt.LoadConfig(strings.NewReader("profile = dev"), INIFormat)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		_, err = opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		for _, format := range []ConfigFormat{JSONFormat, INIFormat} {
+			input := `{"deploy": {"unknown": 1}}`
+			if format == INIFormat {
+				input = "[deploy]\nunknown = 1"
+			}
+			opt, _ := setupConfigTest()
+			err := opt.LoadConfig(strings.NewReader(input), format)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+
+			opt, _ = setupConfigTest()
+			buf := new(bytes.Buffer)
+			opt.Writer = buf
+			opt.SetConfigUnknownMode(Warn)
+			err = opt.LoadConfig(strings.NewReader(input), format)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if buf.String() != "WARNING: "+fmt.Sprintf(text.ErrorConfigUnknownKey, "deploy.unknown", "config")+"\n" {
+				t.Errorf("Unexpected warning: %s", buf.String())
+			}
+
+			opt, _ = setupConfigTest()
+			opt.SetConfigUnknownMode(Fail)
+			err = opt.LoadConfig(strings.NewReader(input), format)
+			if err == nil || err.Error() != fmt.Sprintf(text.ErrorConfigUnknownKey, "deploy.unknown", "config") {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+		}
+		opt, _ := setupConfigTest()
+		opt.SetConfigUnknownMode(Fail)
+		err := opt.LoadConfig(strings.NewReader("[unknown]\nkey = 1"), INIFormat)
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConfigUnknownKey, "unknown.key", "config") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+
+	errorTests := []struct {
+		name   string
+		format ConfigFormat
+		input  string
+		err    string
+	}{
+		{"json syntax", JSONFormat, `{"port": }`, fmt.Sprintf(text.ErrorConfigParse, "config", "invalid character '}' looking for beginning of value")},
+		{"ini syntax", INIFormat, "\nport", fmt.Sprintf(text.ErrorConfigParse, "config", "line 2: expected 'key = value'")},
+		{"json conversion", JSONFormat, `{"port": "abc"}`, fmt.Sprintf(text.ErrorConvertToInt, "config:port", "abc")},
+		{"ini conversion", INIFormat, "port = abc", fmt.Sprintf(text.ErrorConvertToInt, "config:port", "abc")},
+		{"slice conversion", JSONFormat, `{"timeout": ["1m", "2m"]}`, fmt.Sprintf(text.ErrorConfigInvalidValue, "timeout", "config")},
+		{"object", JSONFormat, `{"profile": {"a": "b"}}`, fmt.Sprintf(text.ErrorConfigInvalidValue, "profile", "config")},
+		{"null", JSONFormat, `{"profile": null}`, fmt.Sprintf(text.ErrorConfigInvalidValue, "profile", "config")},
+		{"nested array", JSONFormat, `{"tag": [["a"]]}`, fmt.Sprintf(text.ErrorConfigInvalidValue, "tag", "config")},
+		{"nested map", JSONFormat, `{"label": {"k": ["a", "b"]}}`, fmt.Sprintf(text.ErrorConfigInvalidValue, "label", "config")},
+		{"bool", INIFormat, "debug = yes", fmt.Sprintf(text.ErrorConfigInvalidValue, "debug", "config")},
+		{"bool array", JSONFormat, `{"debug": [true, false]}`, fmt.Sprintf(text.ErrorConfigInvalidValue, "debug", "config")},
+		{"command", JSONFormat, `{"deploy": "x"}`, fmt.Sprintf(text.ErrorConfigInvalidValue, "deploy", "config")},
+		{"command option", JSONFormat, `{"deploy": {"sub": {"name": 1, "x": {}}, "region": []}}`, fmt.Sprintf(text.ErrorConfigInvalidValue, "deploy.region", "config")},
+		{"nested command option", JSONFormat, `{"deploy": {"sub": {"name": []}}}`, fmt.Sprintf(text.ErrorConfigInvalidValue, "deploy.sub.name", "config")},
+		{"map", JSONFormat, `{"label": {"k": "v", "k2": "v2", "k3": "v3", "k4": "v4"}}`, ""},
+		{"ini command option", INIFormat, "[deploy]\nregion = x\n[deploy.sub]\nname = y\n[deploy]\nregion", fmt.Sprintf(text.ErrorConfigParse, "config", "line 6: expected 'key = value'")},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, _ := setupConfigTest()
+			err := opt.LoadConfig(strings.NewReader(tt.input), tt.format)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+		})
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		opt := New()
+		opt.String("format", "json", opt.ValidValues("json", "yaml"))
+		port := opt.Int("port", 80, opt.Max(1000))
+		ids := opt.IntSlice("id", 1, 3)
+		err := opt.LoadConfig(strings.NewReader(`{"format": "xml"}`), JSONFormat)
+		expected := fmt.Sprintf(text.ErrorArgumentNotValidValue, "config:format", "xml", []string{"json", "yaml"})
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		err = opt.LoadConfig(strings.NewReader(`{"port": 8080}`), JSONFormat)
+		expected = fmt.Sprintf(text.ErrorValueGreaterThanMax, "config:port", 8080, 1000)
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		err = opt.LoadConfig(strings.NewReader(`{"id": [1, "x"]}`), JSONFormat)
+		expected = fmt.Sprintf(text.ErrorConvertToInt, "config:id", "x")
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		// A failed value leaves the option unchanged
+		if *port != 80 || len(*ids) != 0 || opt.Called("port") || opt.Called("id") || opt.Value("format") != "json" {
+			t.Errorf("Unexpected values: %d, %v, %v, %v", *port, *ids, opt.Called("port"), opt.Called("id"))
+		}
+	})
+
+	t.Run("custom types", func(t *testing.T) {
+		lvl := logLevel("info")
+		opt := New()
+		opt.Var(&lvl, "level")
+		ids := OptSlice[uint](opt, "id", 1, 3)
+		err := opt.LoadConfig(strings.NewReader(`{"level": "debug", "id": [1, 2]}`), JSONFormat)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if lvl != "debug" || !reflect.DeepEqual(*ids, []uint{1, 2}) {
+			t.Errorf("Unexpected values: %v, %v", lvl, *ids)
+		}
+		err = opt.LoadConfig(strings.NewReader(`{"level": ["debug", "info"]}`), JSONFormat)
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConfigInvalidValue, "level", "config") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+}
+
+func TestSetConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		err := os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return path
+	}
+	tests := []struct {
+		name    string
+		path    string
+		profile string
+		err     string
+	}{
+		{"json", write("app.json", `{"profile": "json"}`), "json", ""},
+		{"ini", write("app.ini", "profile = ini"), "ini", ""},
+		{"cfg", write("app.CFG", "profile = cfg"), "cfg", ""},
+		{"conf", write("app.conf", "profile = conf"), "conf", ""},
+		{"extension", write("app.yaml", "profile: yaml"), "default", fmt.Sprintf(text.ErrorConfigParse, filepath.Join(dir, "app.yaml"), "unknown file extension")},
+		{"missing", filepath.Join(dir, "missing.json"), "default", "open " + filepath.Join(dir, "missing.json") + ": no such file or directory"},
+		{"conversion", write("bad.ini", "port = abc"), "default", fmt.Sprintf(text.ErrorConvertToInt, filepath.Join(dir, "bad.ini")+":port", "abc")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := New()
+			profile := opt.String("profile", "default")
+			opt.Int("port", 80)
+			err := opt.SetConfigFile(tt.path)
+			if tt.err == "" && err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+			if *profile != tt.profile {
+				t.Errorf("Unexpected value: %s", *profile)
+			}
+			if tt.err == "" && opt.CalledAs("profile") != tt.path+":profile" {
+				t.Errorf("Unexpected called as: %s", opt.CalledAs("profile"))
+			}
+		})
+	}
+}
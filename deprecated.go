@@ -0,0 +1,169 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// Deprecated - Marks the option as deprecated.
+// The option still works but using it writes a warning to gopt.Writer, once per parse.
+// The option is excluded from the completions and marked as deprecated in the help.
+//
+// When replacement is not empty, it names the option that replaces the deprecated one and the value is forwarded to it,
+// unless the replacement was set from a source with higher precedence.
+// Both options must be of the same type.
+//
+//     opt.String("zone", "", opt.Deprecated("zones are now regions", "region"))
+//     opt.String("region", "")
+func (gopt *GetOpt) Deprecated(msg, replacement string) ModifyFn {
+	return func(opt *option.Option) {
+		if replacement == opt.Name {
+			panic(fmt.Sprintf("Deprecated option '%s' can't be its own replacement", opt.Name))
+		}
+		opt.SetDeprecated(msg, replacement)
+	}
+}
+
+// SetDeprecated - Marks the command as deprecated.
+// The command still works but calling it with Dispatch writes a warning to gopt.Writer.
+// The command is excluded from the completions and marked as deprecated in the help.
+// replacement is the name of the command that replaces it, it can be empty.
+//
+// NOTE: Call before defining the HelpCommand.
+func (gopt *GetOpt) SetDeprecated(msg, replacement string) *GetOpt {
+	gopt.deprecated = true
+	gopt.deprecatedMsg = msg
+	gopt.replacedBy = replacement
+	gopt.removeCompletionNode()
+	return gopt
+}
+
+// deprecationWarning - Returns the warning for the deprecated option or command.
+func deprecationWarning(format, name, replacement, msg string) string {
+	s := fmt.Sprintf(format, name)
+	if replacement != "" {
+		s += fmt.Sprintf(text.MessageDeprecatedReplacement, replacement)
+	}
+	if msg != "" {
+		s += ": " + msg
+	}
+	return s
+}
+
+func aliasWithDash(alias string) string {
+	if len(alias) == 1 {
+		return "-" + alias
+	}
+	return "--" + alias
+}
+
+// sourcePrecedence - Returns a higher number for the sources that take precedence.
+func sourcePrecedence(s option.Source) int {
+	switch s {
+	case option.CLISource:
+		return 3
+	case option.EnvSource:
+		return 2
+	case option.ConfigSource:
+		return 1
+	}
+	return 0
+}
+
+// replacement - Returns the replacement of the deprecated option, looking it up in the command and its parents.
+func (gopt *GetOpt) replacement(opt *option.Option) *option.Option {
+	for g := gopt; g != nil; g = g.parent {
+		if r, ok := g.obj[opt.ReplacedBy]; ok {
+			return r
+		}
+	}
+	return nil
+}
+
+// checkDeprecated - Returns an error if the replacement of a deprecated option in the command tree is not defined or has a different type.
+// It also resets the warnings so they are shown once per Parse call.
+func (gopt *GetOpt) checkDeprecated(reset bool) error {
+	names := []string{}
+	for name := range gopt.obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opt := gopt.obj[name]
+		if reset {
+			opt.Warned = false
+		}
+		if !opt.IsDeprecated || opt.ReplacedBy == "" {
+			continue
+		}
+		r := gopt.replacement(opt)
+		if r == nil {
+			return fmt.Errorf(text.ErrorDeprecatedReplacementUndefined, opt.Name, opt.ReplacedBy)
+		}
+		if r.OptType != opt.OptType {
+			return fmt.Errorf(text.ErrorDeprecatedReplacementType, opt.Name, opt.ReplacedBy)
+		}
+	}
+	commands := []string{}
+	for name := range gopt.commands {
+		commands = append(commands, name)
+	}
+	sort.Strings(commands)
+	for _, name := range commands {
+		err := gopt.commands[name].checkDeprecated(reset)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleDeprecated - Warns about the deprecated options that were called and forwards their values to their replacements.
+func (gopt *GetOpt) handleDeprecated() error {
+	names := []string{}
+	for name, opt := range gopt.obj {
+		if opt.Called && opt.IsDeprecated {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opt := gopt.obj[name]
+		if !opt.Warned {
+			opt.Warned = true
+			replacement := ""
+			if opt.ReplacedBy != "" {
+				replacement = aliasWithDash(opt.ReplacedBy)
+			}
+			fmt.Fprintf(gopt.Writer, "WARNING: %s\n", deprecationWarning(text.MessageOnDeprecatedOption, aliasWithDash(opt.Name), replacement, opt.DeprecatedMsg))
+		}
+		if opt.ReplacedBy == "" {
+			continue
+		}
+		r := gopt.replacement(opt)
+		if r.Called && sourcePrecedence(r.Source) >= sourcePrecedence(opt.Source) {
+			continue
+		}
+		err := r.CopyValue(opt)
+		if err != nil {
+			return err
+		}
+		r.SetCalledFrom(opt.Source, opt.UsedAlias)
+		err = r.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
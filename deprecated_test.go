@@ -0,0 +1,274 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+func TestDeprecated(t *testing.T) {
+	setup := func() (*GetOpt, *bytes.Buffer) {
+		buf := new(bytes.Buffer)
+		opt := New()
+		opt.Writer = buf
+		opt.StringSlice("zone", 1, 1, opt.Deprecated("zones are now regions", "region"), opt.GetEnv("_GET_OPT_DEPRECATED_ZONE"))
+		opt.StringSlice("region", 1, 1, opt.Alias("r"))
+		opt.Bool("legacy", false, opt.Deprecated("", ""))
+		opt.Int("retries", 0, opt.Deprecated("", "t"))
+		opt.Int("t", 0, opt.Min(1))
+		return opt, buf
+	}
+	zoneWarning := "WARNING: " + fmt.Sprintf(text.MessageOnDeprecatedOption, "--zone") +
+		fmt.Sprintf(text.MessageDeprecatedReplacement, "--region") + ": zones are now regions\n"
+
+	tests := []struct {
+		name    string
+		env     string
+		config  string
+		args    []string
+		region  []string
+		source  option.Source
+		warning string
+	}{
+		{"cli", "", "", []string{"--zone", "a", "--zone", "b"}, []string{"a", "b"}, option.CLISource, zoneWarning},
+		{"replacement on cli", "", "", []string{"--zone", "a", "-r", "b"}, []string{"b"}, option.CLISource, zoneWarning},
+		{"env", "a", "", []string{}, []string{"a"}, option.EnvSource, zoneWarning},
+		{"env and replacement on cli", "a", "", []string{"-r", "b"}, []string{"b"}, option.CLISource, zoneWarning},
+		{"cli over config", "", `{"region": ["b"]}`, []string{"--zone", "a"}, []string{"a"}, option.CLISource, zoneWarning},
+		{"env over config", "a", `{"region": ["b"]}`, []string{}, []string{"a"}, option.EnvSource, zoneWarning},
+		{"config and replacement on cli", "", `{"zone": ["a"]}`, []string{"-r", "b"}, []string{"b"}, option.CLISource, zoneWarning},
+		{"not called", "", "", []string{}, []string{}, option.DefaultSource, ""},
+		{"no replacement", "", "", []string{"--legacy"}, []string{}, option.DefaultSource,
+			"WARNING: " + fmt.Sprintf(text.MessageOnDeprecatedOption, "--legacy") + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("_GET_OPT_DEPRECATED_ZONE", tt.env)
+			}
+			opt, buf := setup()
+			if tt.config != "" {
+				err := opt.LoadConfig(strings.NewReader(tt.config), JSONFormat)
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			}
+			_, err := opt.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if fmt.Sprint(opt.Value("region")) != fmt.Sprint(tt.region) {
+				t.Errorf("Unexpected region: %v", opt.Value("region"))
+			}
+			if source, _ := opt.Source("region"); source != tt.source {
+				t.Errorf("Unexpected source: %s", source)
+			}
+			if buf.String() != tt.warning {
+				t.Errorf("Unexpected warning:\nexpected: %q\ngot:      %q", tt.warning, buf.String())
+			}
+			// The warning is shown once per Parse call
+			_, err = opt.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if buf.String() != strings.Repeat(tt.warning, 2) {
+				t.Errorf("Unexpected warning:\nexpected: %q\ngot:      %q", strings.Repeat(tt.warning, 2), buf.String())
+			}
+		})
+	}
+
+	t.Run("cli over programmatic", func(t *testing.T) {
+		opt, _ := setup()
+		err := opt.SetValue("region", "b")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		_, err = opt.Parse([]string{"--zone", "a"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if fmt.Sprint(opt.Value("region")) != "[a]" {
+			t.Errorf("Unexpected region: %v", opt.Value("region"))
+		}
+	})
+
+	t.Run("replacement validation", func(t *testing.T) {
+		opt, _ := setup()
+		_, err := opt.Parse([]string{"--retries", "0"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorValueLessThanMin, "retries", 0, 1) {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+
+	t.Run("replacement copy error", func(t *testing.T) {
+		lvl := logLevel("info")
+		opt := New()
+		opt.Writer = new(bytes.Buffer)
+		opt.Var(chanValue{}, "old", opt.Deprecated("", "new"))
+		opt.Var(&lvl, "new")
+		_, err := opt.Parse([]string{"--old", "x"})
+		if err == nil {
+			t.Errorf("Expected error")
+		}
+	})
+
+	t.Run("help and completion", func(t *testing.T) {
+		opt, _ := setup()
+		h := opt.Help()
+		if !strings.Contains(h, "(default: false, deprecated)") || !strings.Contains(h, "deprecated, use --region") || !strings.Contains(h, "deprecated, use -t") {
+			t.Errorf("Help doesn't mark deprecated options:\n%s", h)
+		}
+		for _, e := range append(opt.completion.GetChildByName("options").Entries, opt.completion.GetChildByName("options-with-arg").Entries...) {
+			if strings.Contains(e, "zone") || strings.Contains(e, "legacy") || strings.Contains(e, "retries") {
+				t.Errorf("Unexpected option completion: %s", e)
+			}
+		}
+		_, err := opt.Parse([]string{"--zome"})
+		if err == nil || err.Error() != fmt.Sprintf(text.MessageOnUnknown, "zome") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		defer func() { recover() }()
+		opt := New()
+		opt.String("zone", "", opt.Deprecated("", "zone"))
+		t.Errorf("Expected panic")
+	})
+
+	t.Run("replacement errors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			setup    func() *GetOpt
+			expected string
+		}{
+			{"undefined", func() *GetOpt {
+				opt := New()
+				opt.String("zone", "", opt.Deprecated("", "region"))
+				return opt
+			}, fmt.Sprintf(text.ErrorDeprecatedReplacementUndefined, "zone", "region")},
+			{"type", func() *GetOpt {
+				opt := New()
+				opt.String("zone", "", opt.Deprecated("", "region"))
+				opt.Int("region", 0)
+				return opt
+			}, fmt.Sprintf(text.ErrorDeprecatedReplacementType, "zone", "region")},
+			{"command", func() *GetOpt {
+				opt := New()
+				opt.String("region", "")
+				cmd := opt.NewCommand("deploy", "")
+				cmd.Int("zone", 0, opt.Deprecated("", "region"))
+				return opt
+			}, fmt.Sprintf(text.ErrorDeprecatedReplacementType, "zone", "region")},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				buf := new(bytes.Buffer)
+				opt := tt.setup()
+				opt.Writer = buf
+				_, err := opt.Parse([]string{"--zone", "a"})
+				if err == nil || err.Error() != tt.expected {
+					t.Errorf("Error string didn't match expected value: %v", err)
+				}
+				if buf.String() != "" {
+					t.Errorf("Unexpected warning: %s", buf.String())
+				}
+			})
+		}
+	})
+
+	t.Run("warning once per dispatch", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		opt := New()
+		opt.Writer = buf
+		opt.String("zone", "", opt.Deprecated("", "region"))
+		opt.String("region", "")
+		opt.NewCommand("deploy", "").SetCommandFn(func(context.Context, *GetOpt, []string) error { return nil })
+		for i := 0; i < 2; i++ {
+			remaining, err := opt.Parse([]string{"--zone", "a", "deploy"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			err = opt.Dispatch(context.Background(), "help", remaining)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+		warning := "WARNING: " + fmt.Sprintf(text.MessageOnDeprecatedOption, "--zone") + fmt.Sprintf(text.MessageDeprecatedReplacement, "--region") + "\n"
+		if buf.String() != strings.Repeat(warning, 2) {
+			t.Errorf("Unexpected warning:\nexpected: %q\ngot:      %q", strings.Repeat(warning, 2), buf.String())
+		}
+	})
+
+	t.Run("parent replacement", func(t *testing.T) {
+		opt := New()
+		opt.String("region", "")
+		cmd := opt.NewCommand("deploy", "")
+		cmd.Writer = new(bytes.Buffer)
+		cmd.String("zone", "", opt.Deprecated("", "region"))
+		_, err := cmd.Parse([]string{"--zone", "a"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if opt.Value("region") != "a" {
+			t.Errorf("Unexpected region: %v", opt.Value("region"))
+		}
+	})
+}
+
+func TestSetDeprecated(t *testing.T) {
+	fn := func(context.Context, *GetOpt, []string) error { return nil }
+	buf := new(bytes.Buffer)
+	opt := New()
+	opt.Writer = buf
+	opt.NewCommand("deploy", "deploy the app").SetCommandFn(fn)
+	opt.NewCommand("push", "push the app").SetDeprecated("push will be removed", "deploy").SetCommandFn(fn)
+	opt.NewCommand("ship", "ship the app").SetDeprecated("", "").SetCommandFn(fn)
+	opt.HelpCommand("")
+
+	err := opt.Dispatch(context.Background(), "help", []string{"push"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	err = opt.Dispatch(context.Background(), "help", []string{"ship"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := "WARNING: " + fmt.Sprintf(text.MessageOnDeprecatedCommand, "push") +
+		fmt.Sprintf(text.MessageDeprecatedReplacement, "deploy") + ": push will be removed\n" +
+		"WARNING: " + fmt.Sprintf(text.MessageOnDeprecatedCommand, "ship") + "\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected warning:\nexpected: %q\ngot:      %q", expected, buf.String())
+	}
+
+	h := opt.Help()
+	if !strings.Contains(h, "push the app (deprecated, use deploy)") || !strings.Contains(h, "ship the app (deprecated)") {
+		t.Errorf("Help doesn't mark deprecated commands:\n%s", h)
+	}
+	if opt.completion.GetChildByName("push").Name != "" || opt.completion.GetChildByName("deploy").Name != "deploy" {
+		t.Errorf("Unexpected command nodes: %v", opt.completion.Children)
+	}
+	list := opt.completion.GetChildByName("help").GetChildByName("custom").Entries
+	sort.Strings(list)
+	if strings.Join(list, " ") != "deploy help" {
+		t.Errorf("Unexpected help completions: %v", list)
+	}
+	err = opt.Dispatch(context.Background(), "help", []string{"psh"})
+	if err == nil || err.Error() != "not a command: 'psh'" {
+		t.Errorf("Error string didn't match expected value: %v", err)
+	}
+}
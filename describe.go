@@ -0,0 +1,208 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// describeWriter - Writer where the DescribeOption output will be written to.
+var describeWriter io.Writer = os.Stdout
+
+// CommandInfo - Serializable description of a command, its options, arguments and children.
+// See Describe.
+type CommandInfo struct {
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	Hidden        bool           `json:"hidden,omitempty"`
+	Deprecated    bool           `json:"deprecated,omitempty"`
+	DeprecatedMsg string         `json:"deprecated_msg,omitempty"`
+	ReplacedBy    string         `json:"replaced_by,omitempty"`
+	Options       []OptionInfo   `json:"options"`
+	Arguments     []ArgumentInfo `json:"arguments,omitempty"`
+	Commands      []CommandInfo  `json:"commands,omitempty"`
+}
+
+// OptionInfo - Serializable description of an option.
+// Type is the option.Type name, ArgName the custom type name or the name given with ArgName.
+// Completion is one of none, file, dir or list, options with ValidValues complete the list of valid values.
+type OptionInfo struct {
+	Name           string   `json:"name"`
+	Aliases        []string `json:"aliases,omitempty"`
+	Type           string   `json:"type"`
+	ArgName        string   `json:"arg_name,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Default        string   `json:"default"`
+	EnvVar         string   `json:"env_var,omitempty"`
+	Required       bool     `json:"required"`
+	MinArgs        int      `json:"min_args,omitempty"`
+	MaxArgs        int      `json:"max_args,omitempty"`
+	ValidValues    []string `json:"valid_values,omitempty"`
+	Completion     string   `json:"completion"`
+	CompletionList []string `json:"completion_list,omitempty"`
+	Group          string   `json:"group,omitempty"`
+	Hidden         bool     `json:"hidden,omitempty"`
+	Deprecated     bool     `json:"deprecated,omitempty"`
+	DeprecatedMsg  string   `json:"deprecated_msg,omitempty"`
+	ReplacedBy     string   `json:"replaced_by,omitempty"`
+}
+
+// ArgumentInfo - Serializable description of a positional argument.
+// Completion is one of none, file, dir or list, arguments with ValidValues complete the list of valid values.
+type ArgumentInfo struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Description    string   `json:"description,omitempty"`
+	Required       bool     `json:"required"`
+	Variadic       bool     `json:"variadic,omitempty"`
+	ValidValues    []string `json:"valid_values,omitempty"`
+	Completion     string   `json:"completion"`
+	CompletionList []string `json:"completion_list,omitempty"`
+}
+
+// Describe - Returns a serializable description of the command and its children.
+// Options and commands are sorted by name, arguments are in definition order.
+// Options inherited from the parent are only described in the parent.
+//
+// Hidden options and commands, unless shown with ShowHiddenEnvVar, and the help command are skipped.
+func (gopt *GetOpt) Describe() CommandInfo {
+	gopt.passOptionsToChildren()
+	return gopt.describe()
+}
+
+func (gopt *GetOpt) describe() CommandInfo {
+	info := CommandInfo{
+		Name:          gopt.name,
+		Description:   gopt.description,
+		Hidden:        gopt.hidden,
+		Deprecated:    gopt.deprecated,
+		DeprecatedMsg: gopt.deprecatedMsg,
+		ReplacedBy:    gopt.replacedBy,
+		Options:       []OptionInfo{},
+	}
+	options, _ := gopt.helpOptionsByOrigin()
+	sort.Slice(options, func(i, j int) bool { return options[i].Name < options[j].Name })
+	for _, opt := range options {
+		info.Options = append(info.Options, describeOption(opt))
+	}
+	for _, arg := range gopt.positionals {
+		completion, list := describeCompletion(arg)
+		info.Arguments = append(info.Arguments, ArgumentInfo{
+			Name:           arg.Name,
+			Type:           arg.OptType.String(),
+			Description:    arg.Description,
+			Required:       arg.IsRequired,
+			Variadic:       isArgTail(arg),
+			ValidValues:    arg.ValidValues,
+			Completion:     completion,
+			CompletionList: list,
+		})
+	}
+	commands := gopt.helpCommands()
+	sort.Slice(commands, func(i, j int) bool { return commands[i].name < commands[j].name })
+	for _, command := range commands {
+		if command.isHelpCommand {
+			continue
+		}
+		info.Commands = append(info.Commands, command.describe())
+	}
+	return info
+}
+
+// describeCompletion - Returns the completion source and list, the valid values are completed unless a completion was given.
+func describeCompletion(opt *option.Option) (string, []string) {
+	if opt.Completion == option.NoCompletion && len(opt.ValidValues) > 0 {
+		return option.ListCompletion.String(), opt.ValidValues
+	}
+	return opt.Completion.String(), opt.CompletionList
+}
+
+func describeOption(opt *option.Option) OptionInfo {
+	aliases := []string{}
+	for _, alias := range opt.Aliases {
+		if alias != opt.Name {
+			aliases = append(aliases, alias)
+		}
+	}
+	argName := ""
+	if opt.OptType != option.BoolType {
+		argName = opt.HelpArgName
+	}
+	completion, list := describeCompletion(opt)
+	return OptionInfo{
+		Name:           opt.Name,
+		Aliases:        aliases,
+		Type:           opt.OptType.String(),
+		ArgName:        argName,
+		Description:    opt.Description,
+		Default:        opt.DefaultStr,
+		EnvVar:         opt.EnvVar,
+		Required:       opt.IsRequired,
+		MinArgs:        opt.MinArgs,
+		MaxArgs:        opt.MaxArgs,
+		ValidValues:    opt.ValidValues,
+		Completion:     completion,
+		CompletionList: list,
+		Group:          opt.Group,
+		Hidden:         opt.IsHidden,
+		Deprecated:     opt.IsDeprecated,
+		DeprecatedMsg:  opt.DeprecatedMsg,
+		ReplacedBy:     opt.ReplacedBy,
+	}
+}
+
+// DescribeJSON - Writes the description of the command and its children in JSON format.
+// See Describe.
+func (gopt *GetOpt) DescribeJSON(w io.Writer) error {
+	b, err := json.MarshalIndent(gopt.Describe(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+// DescribeOption - Defines a hidden bool option that writes the description of the program, in JSON format, to stdout and exits.
+// It allows other programs to consume the program interface without parsing the help.
+//
+//     opt.DescribeOption("help-json")
+//
+// Called as `prog --help-json`.
+// See Describe.
+func (gopt *GetOpt) DescribeOption(name string, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.BoolType, new(bool))
+	opt.DefaultStr = "false"
+	opt.Description = text.DescribeOptionDescription
+	opt.IsHidden = true
+	opt.Handler = func(optName string, argument string, usedAlias string) error {
+		root := gopt
+		for root.parent != nil {
+			root = root.parent
+		}
+		err := root.DescribeJSON(describeWriter)
+		if err != nil {
+			return err
+		}
+		exitFn(0)
+		return nil
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionAppendAliases(opt)
+	gopt.setOption(opt)
+}
@@ -0,0 +1,209 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/DavidGamba/go-getoptions/option"
+)
+
+func TestDescribe(t *testing.T) {
+	fn := func(context.Context, *GetOpt, []string) error { return nil }
+	setup := func() *GetOpt {
+		opt := New()
+		opt.Self("prog", "Program that does things.")
+		opt.Bool("debug", false, opt.GetEnv("PROG_DEBUG"), opt.Alias("d"))
+		opt.DescribeOption("help-json", opt.Alias("J"))
+		deploy := opt.NewCommand("deploy", "deploy the app")
+		deploy.StringSlice("region", 1, 3, opt.Description("AWS regions"), opt.Required(), opt.ValidValues("us-east-1", "eu-west-1"), opt.Group("Networking"))
+		deploy.StringSlice("zone", 1, 3, opt.Deprecated("zones are now regions", "region"))
+		deploy.Arg("src", option.StringType, true, opt.CompleteFiles())
+		deploy.Arg("dst", option.StringRepeatType, false, opt.CompleteList("a", "b"), opt.Description("Destinations"))
+		deploy.SetCommandFn(fn)
+		opt.NewCommand("push", "").SetDeprecated("", "deploy").SetCommandFn(fn)
+		opt.NewCommand("secret", "").SetHidden().SetCommandFn(fn)
+		opt.HelpCommand("")
+		return opt
+	}
+	expected := `{
+  "name": "prog",
+  "description": "Program that does things.",
+  "options": [
+    {
+      "name": "debug",
+      "aliases": [
+        "d"
+      ],
+      "type": "bool",
+      "default": "false",
+      "env_var": "PROG_DEBUG",
+      "required": false,
+      "completion": "none"
+    }
+  ],
+  "commands": [
+    {
+      "name": "deploy",
+      "description": "deploy the app",
+      "options": [
+        {
+          "name": "region",
+          "type": "[]string",
+          "arg_name": "string",
+          "description": "AWS regions",
+          "default": "[]",
+          "required": true,
+          "min_args": 1,
+          "max_args": 3,
+          "valid_values": [
+            "us-east-1",
+            "eu-west-1"
+          ],
+          "completion": "list",
+          "completion_list": [
+            "us-east-1",
+            "eu-west-1"
+          ],
+          "group": "Networking"
+        },
+        {
+          "name": "zone",
+          "type": "[]string",
+          "arg_name": "string",
+          "default": "[]",
+          "required": false,
+          "min_args": 1,
+          "max_args": 3,
+          "completion": "none",
+          "deprecated": true,
+          "deprecated_msg": "zones are now regions",
+          "replaced_by": "region"
+        }
+      ],
+      "arguments": [
+        {
+          "name": "src",
+          "type": "string",
+          "required": true,
+          "completion": "file"
+        },
+        {
+          "name": "dst",
+          "type": "[]string",
+          "description": "Destinations",
+          "required": false,
+          "variadic": true,
+          "completion": "list",
+          "completion_list": [
+            "a",
+            "b"
+          ]
+        }
+      ]
+    },
+    {
+      "name": "push",
+      "deprecated": true,
+      "replaced_by": "deploy",
+      "options": []
+    }
+  ]
+}
+`
+
+	t.Run("json", func(t *testing.T) {
+		opt := setup()
+		buf := new(bytes.Buffer)
+		err := opt.DescribeJSON(buf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if buf.String() != expected {
+			t.Errorf("Unexpected description:\n%s\n%s", buf.String(), firstDiff(buf.String(), expected))
+		}
+		var info CommandInfo
+		err = json.Unmarshal(buf.Bytes(), &info)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if info.Commands[0].Options[0].MaxArgs != 3 {
+			t.Errorf("Unexpected round trip: %v", info)
+		}
+		err = opt.DescribeJSON(failWriter{})
+		if err == nil {
+			t.Errorf("Expected error")
+		}
+	})
+
+	t.Run("show hidden", func(t *testing.T) {
+		t.Setenv(ShowHiddenEnvVar, "true")
+		info := setup().Describe()
+		if len(info.Options) != 2 || info.Options[1].Name != "help-json" || !info.Options[1].Hidden {
+			t.Errorf("Unexpected options: %v", info.Options)
+		}
+		if len(info.Commands) != 3 || info.Commands[2].Name != "secret" || !info.Commands[2].Hidden {
+			t.Errorf("Unexpected commands: %v", info.Commands)
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		opt := New()
+		opt.String("format", "", opt.ValidValues("json", "yaml"))
+		opt.String("file", "", opt.CompleteFiles())
+		opt.Arg("env", option.StringType, true, opt.ValidValues("dev", "prod"))
+		info := opt.Describe()
+		if info.Options[0].Name != "file" || info.Options[0].Completion != "file" || info.Options[0].CompletionList != nil {
+			t.Errorf("Unexpected option: %v", info.Options[0])
+		}
+		if info.Options[1].Completion != "list" || strings.Join(info.Options[1].CompletionList, " ") != "json yaml" {
+			t.Errorf("Unexpected option: %v", info.Options[1])
+		}
+		if info.Arguments[0].Completion != "list" || strings.Join(info.Arguments[0].CompletionList, " ") != "dev prod" {
+			t.Errorf("Unexpected argument: %v", info.Arguments[0])
+		}
+	})
+
+	t.Run("option", func(t *testing.T) {
+		defer func(fn func(int)) { exitFn = fn }(exitFn)
+		defer func(w io.Writer) { describeWriter = w }(describeWriter)
+		code := -1
+		exitFn = func(c int) { code = c }
+
+		buf := new(bytes.Buffer)
+		describeWriter = buf
+		opt := setup()
+		_, err := opt.Parse([]string{"deploy", "--help-json"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if code != 0 {
+			t.Errorf("Unexpected exit code: %d", code)
+		}
+		if buf.String() != expected {
+			t.Errorf("Unexpected description:\n%s\n%s", buf.String(), firstDiff(buf.String(), expected))
+		}
+
+		describeWriter = failWriter{}
+		code = -1
+		opt = setup()
+		_, err = opt.Parse([]string{"--help-json"})
+		if err == nil {
+			t.Errorf("Expected error")
+		}
+		if code != -1 {
+			t.Errorf("Unexpected exit code: %d", code)
+		}
+	})
+}
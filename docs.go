@@ -0,0 +1,49 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"github.com/DavidGamba/go-getoptions/help"
+)
+
+// DocFormat - Format of the generated reference documentation.
+type DocFormat = help.DocFormat
+
+// Documentation formats
+const (
+	MarkdownFormat = help.MarkdownFormat
+	AsciiDocFormat = help.AsciiDocFormat
+)
+
+// Docs - Returns the Markdown or AsciiDoc reference pages of the command and its children indexed by file name.
+// For example: `prog.md` and `prog-deploy.md`.
+//
+// Each page includes the synopsis, description, a list of the child commands linking to their pages,
+// a table of the options with their type, default, environment variable, required flag and aliases
+// and a link to the parent command page.
+// The output is deterministic so it can be checked into version control.
+//
+// Hidden commands, unless shown with ShowHiddenEnvVar, and the help command are skipped.
+func (gopt *GetOpt) Docs(format DocFormat) map[string]string {
+	gopt.passOptionsToChildren()
+	pages := map[string]string{}
+	for _, command := range gopt.docCommands() {
+		page := command.helpPage()
+		pages[help.DocPageName(page.Name, format)] = help.Doc(page, format)
+	}
+	return pages
+}
+
+// WriteDocs - Writes the Markdown or AsciiDoc reference pages of the command and its children to dir.
+// See Docs.
+//
+//     err := opt.WriteDocs("./docs/cli", getoptions.MarkdownFormat)
+func (gopt *GetOpt) WriteDocs(dir string, format DocFormat) error {
+	return writePages(dir, gopt.Docs(format))
+}
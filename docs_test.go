@@ -0,0 +1,88 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDocs(t *testing.T) {
+	fn := func(context.Context, *GetOpt, []string) error { return nil }
+	setup := func() *GetOpt {
+		opt := New()
+		opt.Self("prog", "Program that does things.")
+		opt.Bool("debug", false, opt.GetEnv("PROG_DEBUG"), opt.Alias("d"))
+		deploy := opt.NewCommand("deploy", "deploy the app")
+		deploy.String("region", "", opt.Description("AWS region"), opt.Required())
+		deploy.SetCommandFn(fn)
+		opt.NewCommand("secret", "").SetHidden().SetCommandFn(fn)
+		opt.HelpCommand("")
+		return opt
+	}
+
+	tests := []struct {
+		name     string
+		format   DocFormat
+		files    string
+		contains map[string]string
+	}{
+		{"markdown", MarkdownFormat, "prog-deploy.md prog.md", map[string]string{
+			"prog.md":        "* [deploy](prog-deploy.md) - deploy the app\n",
+			"prog-deploy.md": "| `--region` |  | string |  |  | yes | AWS region |\n",
+		}},
+		{"asciidoc", AsciiDocFormat, "prog-deploy.adoc prog.adoc", map[string]string{
+			"prog.adoc":        "xref:prog-deploy.adoc[deploy]:: deploy the app\n",
+			"prog-deploy.adoc": "=== GLOBAL OPTIONS\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := setup()
+			pages := opt.Docs(tt.format)
+			names := []string{}
+			for name := range pages {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if strings.Join(names, " ") != tt.files {
+				t.Errorf("Unexpected pages: %v", names)
+			}
+			for name, s := range tt.contains {
+				if !strings.Contains(pages[name], s) {
+					t.Errorf("Page %s doesn't contain %q:\n%s", name, s, pages[name])
+				}
+			}
+			for name, page := range pages {
+				if strings.Contains(page, "secret") || strings.Contains(page, "help") {
+					t.Errorf("Unexpected command in page %s:\n%s", name, page)
+				}
+			}
+
+			dir := filepath.Join(t.TempDir(), "docs")
+			err := opt.WriteDocs(dir, tt.format)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			for name, page := range pages {
+				b, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				if string(b) != page {
+					t.Errorf("Unexpected page %s:\n%s", name, string(b))
+				}
+			}
+		})
+	}
+}
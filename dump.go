@@ -0,0 +1,127 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/DavidGamba/go-getoptions/option"
+)
+
+// DumpFormat - Output format of Dump.
+type DumpFormat int
+
+// Dump formats
+const (
+	TextDump DumpFormat = iota
+	JSONDump
+)
+
+// redacted - Value shown by Dump for secret options.
+const redacted = "*****"
+
+// dumpEntry - JSON representation of an option value.
+type dumpEntry struct {
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+	Detail string      `json:"detail,omitempty"`
+}
+
+// Dump - Writes the resolved value of every option and where it came from, sorted by option name.
+// Secret options have their value redacted, see Secret.
+//
+// The text format has one option per line:
+//
+//     debug = false (default)
+//     password = ***** (env: DB_PASSWORD)
+//     profile = "dev" (cli: -p)
+//     region = "us-west-2" (config: config.json:deploy.region)
+//
+// The JSON format is an object indexed by option name:
+//
+//     {"profile": {"value": "dev", "source": "cli", "detail": "-p"}}
+//
+// NOTE: Call after Parse.
+func (gopt *GetOpt) Dump(w io.Writer, format DumpFormat) error {
+	names := []string{}
+	for name := range gopt.obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if format == JSONDump {
+		m := map[string]dumpEntry{}
+		for _, name := range names {
+			opt := gopt.obj[name]
+			m[name] = dumpEntry{Value: dumpValue(opt), Source: opt.Source.String(), Detail: dumpDetail(opt)}
+		}
+		b, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+	for _, name := range names {
+		opt := gopt.obj[name]
+		v := dumpValue(opt)
+		if s, ok := opt.Value().(string); ok && !opt.IsSecret {
+			v = fmt.Sprintf("%q", s)
+		}
+		source := opt.Source.String()
+		if detail := dumpDetail(opt); detail != "" {
+			source += ": " + detail
+		}
+		_, err := fmt.Fprintf(w, "%s = %v (%s)\n", name, v, source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpValue - Returns the option value with durations, times and custom types as strings.
+func dumpValue(opt *option.Option) interface{} {
+	if opt.IsSecret {
+		return redacted
+	}
+	switch v := opt.Value().(type) {
+	case time.Duration:
+		return v.String()
+	case []time.Duration:
+		s := []string{}
+		for _, d := range v {
+			s = append(s, d.String())
+		}
+		return s
+	case time.Time:
+		return v.Format(opt.TimeLayout)
+	case Value:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+// dumpDetail - Returns the alias, environment variable or configuration key that set the value.
+func dumpDetail(opt *option.Option) string {
+	switch opt.Source {
+	case option.DefaultSource, option.ProgrammaticSource:
+		return ""
+	case option.CLISource:
+		if len(opt.UsedAlias) == 1 {
+			return "-" + opt.UsedAlias
+		}
+		return "--" + opt.UsedAlias
+	}
+	return opt.UsedAlias
+}
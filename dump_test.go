@@ -0,0 +1,298 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+func setupDumpTest(t *testing.T) *GetOpt {
+	os.Setenv("_get_opt_dump_password", "hunter2")
+	os.Setenv("_get_opt_dump_user", "admin")
+	t.Cleanup(func() {
+		os.Unsetenv("_get_opt_dump_password")
+		os.Unsetenv("_get_opt_dump_user")
+	})
+	lvl := logLevel("info")
+	opt := New()
+	opt.Bool("debug", false)
+	opt.String("profile", "default", opt.Alias("p"))
+	opt.String("password", "", opt.Secret(), opt.GetEnv("_get_opt_dump_password"))
+	opt.String("user", "", opt.GetEnv("_get_opt_dump_user"))
+	opt.String("region", "us-east-1")
+	opt.Int("port", 80)
+	opt.Duration("timeout", time.Second)
+	opt.DurationSlice("wait", 1, 1)
+	opt.Time("since", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	opt.StringSlice("tag", 1, 3)
+	opt.StringMap("label", 1, 3)
+	opt.Var(&lvl, "level")
+	err := opt.LoadConfig(strings.NewReader(`{"region": "us-west-2", "tag": ["a"]}`), JSONFormat)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	err = opt.SetValue("port", "8080")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	_, err = opt.Parse([]string{"-p", "dev", "--wait", "1m", "--label", "k=v", "--level", "debug"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return opt
+}
+
+func TestSource(t *testing.T) {
+	opt := setupDumpTest(t)
+	tests := []struct {
+		name   string
+		source option.Source
+		detail string
+	}{
+		{"debug", option.DefaultSource, ""},
+		{"profile", option.CLISource, "p"},
+		{"user", option.EnvSource, "_get_opt_dump_user"},
+		{"region", option.ConfigSource, "config:region"},
+		{"port", option.ProgrammaticSource, "port"},
+		{"unknown", option.DefaultSource, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, detail := opt.Source(tt.name)
+			if source != tt.source || detail != tt.detail {
+				t.Errorf("Unexpected source: %s, %s", source, detail)
+			}
+		})
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	t.Run("precedence", func(t *testing.T) {
+		opt := New()
+		tags := opt.StringSlice("tag", 1, 3)
+		labels := opt.StringMap("label", 1, 3)
+		port := opt.Int("port", 80)
+		cmd := opt.NewCommand("cmd", "")
+		err := opt.SetValue("tag", "a", "b")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		err = cmd.SetValue("label", "k=v")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		err = opt.SetValue("port", "8080")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		_, err = opt.Parse([]string{"--tag", "c", "--port", "9090"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*tags, []string{"c"}) || !reflect.DeepEqual(labels, map[string]string{"k": "v"}) || *port != 9090 {
+			t.Errorf("Unexpected values: %v, %v, %d", *tags, labels, *port)
+		}
+		if source, _ := opt.Source("port"); source != option.CLISource {
+			t.Errorf("Unexpected source: %s", source)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		opt := New()
+		port := opt.Int("port", 80, opt.Max(1000))
+		ids := opt.IntSlice("id", 1, 99)
+		err := opt.SetValue("port", "x")
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToInt, "port", "x") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		err = opt.SetValue("port", "8080")
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorValueGreaterThanMax, "port", 8080, 1000) {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		if *port != 80 || opt.Called("port") {
+			t.Errorf("Unexpected value: %d, %v", *port, opt.Called("port"))
+		}
+
+		// The previous value and source are kept
+		err = opt.SetValue("id", "1", "2")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.SetValue("id", "3", "x")
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToInt, "id", "x") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		if !reflect.DeepEqual(*ids, []int{1, 2}) {
+			t.Errorf("Unexpected value: %v", *ids)
+		}
+		_, err = opt.Parse([]string{"--id", "4"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*ids, []int{4}) {
+			t.Errorf("Unexpected value: %v", *ids)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetValue did not panic")
+			}
+		}()
+		New().SetValue("unknown", "x")
+	})
+}
+
+func TestDump(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		opt := setupDumpTest(t)
+		buf := new(bytes.Buffer)
+		err := opt.Dump(buf, TextDump)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := `debug = false (default)
+label = map[k:v] (cli: --label)
+level = debug (cli: --level)
+password = ***** (env: _get_opt_dump_password)
+port = 8080 (programmatic)
+profile = "dev" (cli: -p)
+region = "us-west-2" (config: config:region)
+since = 2021-01-01T00:00:00Z (default)
+tag = [a] (config: config:tag)
+timeout = 1s (default)
+user = "admin" (env: _get_opt_dump_user)
+wait = [1m0s] (cli: --wait)
+`
+		if buf.String() != expected {
+			t.Errorf("Unexpected dump:\n%s\n%s", buf.String(), firstDiff(buf.String(), expected))
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		opt := setupDumpTest(t)
+		buf := new(bytes.Buffer)
+		err := opt.Dump(buf, JSONDump)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := `{
+  "debug": {
+    "value": false,
+    "source": "default"
+  },
+  "label": {
+    "value": {
+      "k": "v"
+    },
+    "source": "cli",
+    "detail": "--label"
+  },
+  "level": {
+    "value": "debug",
+    "source": "cli",
+    "detail": "--level"
+  },
+  "password": {
+    "value": "*****",
+    "source": "env",
+    "detail": "_get_opt_dump_password"
+  },
+  "port": {
+    "value": 8080,
+    "source": "programmatic"
+  },
+  "profile": {
+    "value": "dev",
+    "source": "cli",
+    "detail": "-p"
+  },
+  "region": {
+    "value": "us-west-2",
+    "source": "config",
+    "detail": "config:region"
+  },
+  "since": {
+    "value": "2021-01-01T00:00:00Z",
+    "source": "default"
+  },
+  "tag": {
+    "value": [
+      "a"
+    ],
+    "source": "config",
+    "detail": "config:tag"
+  },
+  "timeout": {
+    "value": "1s",
+    "source": "default"
+  },
+  "user": {
+    "value": "admin",
+    "source": "env",
+    "detail": "_get_opt_dump_user"
+  },
+  "wait": {
+    "value": [
+      "1m0s"
+    ],
+    "source": "cli",
+    "detail": "--wait"
+  }
+}
+`
+		if buf.String() != expected {
+			t.Errorf("Unexpected dump:\n%s\n%s", buf.String(), firstDiff(buf.String(), expected))
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		opt := New()
+		opt.Bool("debug", false)
+		err := opt.Dump(failWriter{}, TextDump)
+		if err == nil || err.Error() != "write error" {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		err = opt.Dump(failWriter{}, JSONDump)
+		if err == nil || err.Error() != "write error" {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		opt.Var(chanValue{}, "chan")
+		err = opt.Dump(new(bytes.Buffer), JSONDump)
+		if err == nil {
+			t.Errorf("Expected error")
+		}
+	})
+}
+
+// failWriter - io.Writer that always fails.
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+// chanValue - Getter whose value can't be encoded as JSON.
+type chanValue struct{}
+
+func (chanValue) Set(s string) error { return nil }
+func (chanValue) String() string     { return "" }
+func (chanValue) Type() string       { return "chan" }
+func (chanValue) Get() interface{}   { return make(chan int) }
@@ -0,0 +1,107 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// ConversionError - Error returned when an argument can't be converted to the option type.
+// See option.ConversionError.
+type ConversionError = option.ConversionError
+
+// RequiredOptionError - Error returned when a required option wasn't called.
+// See option.RequiredOptionError.
+type RequiredOptionError = option.RequiredOptionError
+
+// UnknownOptionError - Error returned when an unknown option is called and the UnknownMode is Fail.
+type UnknownOptionError struct {
+	Alias       string   // Alias as it was called.
+	Dash        string   // Dash prefix as it was called, shown with the alias when there are suggestions.
+	Suggestions []string // Similar aliases, see DisableSuggestions.
+}
+
+func (e *UnknownOptionError) Error() string {
+	alias := e.Alias
+	list := []string{}
+	for _, s := range e.Suggestions {
+		// Use the same form for the alias and the suggestions
+		alias = e.Dash + e.Alias
+		list = append(list, "'"+aliasWithDash(s)+"'")
+	}
+	return fmt.Sprintf(text.MessageOnUnknown, alias) + didYouMean(list)
+}
+
+// MissingArgumentError - Error returned when an option that requires an argument is called without one.
+type MissingArgumentError struct {
+	Name  string // Option name.
+	Alias string // Alias used to call the option.
+	Arg   string // Next argument when it looks like an option, empty when there are no more arguments.
+}
+
+func (e *MissingArgumentError) Error() string {
+	if e.Arg != "" {
+		return fmt.Sprintf(text.ErrorArgumentWithDash, e.Alias)
+	}
+	return fmt.Sprintf(text.ErrorMissingArgument, e.Alias)
+}
+
+// AmbiguousOptionError - Error returned when an alias matches more than one option.
+type AmbiguousOptionError struct {
+	Alias      string   // Alias as it was called.
+	Candidates []string // Sorted aliases matched.
+}
+
+func (e *AmbiguousOptionError) Error() string {
+	return fmt.Sprintf(text.ErrorAmbiguousArgument, e.Alias, e.Candidates)
+}
+
+// AmbiguousCommandError - Error returned by Dispatch when the argument matches more than one command ignoring case.
+type AmbiguousCommandError struct {
+	Name       string   // Argument as it was passed.
+	Candidates []string // Sorted command names matched.
+}
+
+func (e *AmbiguousCommandError) Error() string {
+	return fmt.Sprintf(text.ErrorAmbiguousCommand, e.Name, e.Candidates)
+}
+
+// UnknownCommandError - Error returned by Dispatch when the argument is not a command.
+type UnknownCommandError struct {
+	Name        string   // Argument as it was passed.
+	Help        bool     // True when the argument was passed to the help command.
+	Suggestions []string // Similar command names, see DisableSuggestions.
+}
+
+func (e *UnknownCommandError) Error() string {
+	list := []string{}
+	for _, s := range e.Suggestions {
+		list = append(list, "'"+s+"'")
+	}
+	if e.Help {
+		return fmt.Sprintf(text.ErrorUnknownHelpEntry, e.Name) + didYouMean(list)
+	}
+	if strings.HasPrefix(e.Name, "-") {
+		return fmt.Sprintf(text.ErrorUnknownCommandOrOption, e.Name)
+	}
+	return fmt.Sprintf(text.ErrorUnknownCommand, e.Name) + didYouMean(list)
+}
+
+// errNoMoreArguments - Internal error used to stop reading arguments for options that take multiple arguments.
+var errNoMoreArguments = errors.New("NoMoreArguments")
+
+func isMissingArgumentError(err error) bool {
+	var e *MissingArgumentError
+	return errors.As(err, &e)
+}
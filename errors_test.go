@@ -0,0 +1,167 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+func TestErrorTypes(t *testing.T) {
+	setup := func() *GetOpt {
+		lvl := logLevel("info")
+		opt := New()
+		opt.String("profile", "", opt.Alias("p"))
+		opt.String("region", "", opt.Required())
+		opt.String("role", "")
+		opt.Int("port", 0)
+		opt.Float64("ratio", 0)
+		opt.Duration("timeout", 0)
+		opt.Time("since", time.Time{})
+		opt.IntSlice("ids", 1, 3)
+		opt.StringSlice("tag", 2, 3)
+		opt.Var(&lvl, "level")
+		return opt
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		target interface{}
+		expect interface{}
+		msg    string
+	}{
+		{"unknown", []string{"--regoin", "a"}, &UnknownOptionError{},
+			&UnknownOptionError{Alias: "regoin", Dash: "--", Suggestions: []string{"region"}},
+			"Unknown option '--regoin', did you mean '--region'?"},
+		{"missing argument", []string{"--region", "a", "-p"}, &MissingArgumentError{},
+			&MissingArgumentError{Name: "profile", Alias: "p"},
+			fmt.Sprintf(text.ErrorMissingArgument, "p")},
+		{"missing argument with dash", []string{"--profile", "--region", "a"}, &MissingArgumentError{},
+			&MissingArgumentError{Name: "profile", Alias: "profile", Arg: "--region"},
+			fmt.Sprintf(text.ErrorArgumentWithDash, "profile")},
+		{"missing slice argument", []string{"--region", "a", "--tag", "x"}, &MissingArgumentError{},
+			&MissingArgumentError{Name: "tag", Alias: "tag"},
+			fmt.Sprintf(text.ErrorMissingArgument, "tag")},
+		{"missing slice argument with dash", []string{"--tag", "x", "--region", "a"}, &MissingArgumentError{},
+			&MissingArgumentError{Name: "tag", Alias: "tag", Arg: "--region"},
+			fmt.Sprintf(text.ErrorArgumentWithDash, "tag")},
+		{"ambiguous", []string{"--r", "a"}, &AmbiguousOptionError{},
+			&AmbiguousOptionError{Alias: "r", Candidates: []string{"ratio", "region", "role"}},
+			fmt.Sprintf(text.ErrorAmbiguousArgument, "r", []string{"ratio", "region", "role"})},
+		{"int", []string{"--region", "a", "--port", "x"}, &ConversionError{},
+			&ConversionError{Name: "port", Alias: "port", Arg: "x", Type: "int"},
+			fmt.Sprintf(text.ErrorConvertToInt, "port", "x")},
+		{"int range", []string{"--region", "a", "--ids", "3..1"}, &ConversionError{},
+			&ConversionError{Name: "ids", Alias: "ids", Arg: "3..1", Type: "int"},
+			fmt.Sprintf(text.ErrorConvertToInt, "ids", "3..1")},
+		{"float64", []string{"--region", "a", "--ratio", "x"}, &ConversionError{},
+			&ConversionError{Name: "ratio", Alias: "ratio", Arg: "x", Type: "float64"},
+			fmt.Sprintf(text.ErrorConvertToFloat64, "ratio", "x")},
+		{"duration", []string{"--region", "a", "--timeout", "x"}, &ConversionError{},
+			&ConversionError{Name: "timeout", Alias: "timeout", Arg: "x", Type: "duration"},
+			fmt.Sprintf(text.ErrorConvertToDuration, "timeout", "x")},
+		{"time", []string{"--region", "a", "--since", "x"}, &ConversionError{},
+			&ConversionError{Name: "since", Alias: "since", Arg: "x", Type: "time", Layout: "2006-01-02T15:04:05Z07:00"},
+			fmt.Sprintf(text.ErrorConvertToTime, "since", "x", "2006-01-02T15:04:05Z07:00")},
+		{"required", []string{}, &RequiredOptionError{},
+			&RequiredOptionError{Name: "region"},
+			fmt.Sprintf(text.ErrorMissingRequiredOption, "region")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := setup()
+			_, err := opt.Parse(tt.args)
+			if err == nil {
+				t.Fatalf("Expected error")
+			}
+			if err.Error() != tt.msg {
+				t.Errorf("Error string didn't match expected value:\nexpected: %s\ngot:      %s", tt.msg, err)
+			}
+			target := reflect.New(reflect.TypeOf(tt.target))
+			if !errors.As(err, target.Interface()) {
+				t.Fatalf("Expected error of type %T, got %T", tt.target, err)
+			}
+			if !reflect.DeepEqual(target.Elem().Interface(), tt.expect) {
+				t.Errorf("Error didn't match expected value:\nexpected: %#v\ngot:      %#v", tt.expect, target.Elem().Interface())
+			}
+		})
+	}
+
+	t.Run("custom value", func(t *testing.T) {
+		opt := setup()
+		_, err := opt.Parse([]string{"--region", "a", "--level", "x"})
+		var e *ConversionError
+		if !errors.As(err, &e) {
+			t.Fatalf("Expected ConversionError, got %T", err)
+		}
+		if e.Name != "level" || e.Arg != "x" || e.Type != "level" || e.Unwrap() == nil || e.Unwrap().Error() != "unknown level" {
+			t.Errorf("Unexpected error: %#v", e)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorConvertToValue, "level", "level", "x", "unknown level") {
+			t.Errorf("Unexpected error string: %s", err)
+		}
+	})
+
+	t.Run("required with custom message", func(t *testing.T) {
+		opt := New()
+		opt.String("region", "", opt.Required("region is required"))
+		_, err := opt.Parse([]string{})
+		var e *RequiredOptionError
+		if !errors.As(err, &e) {
+			t.Fatalf("Expected RequiredOptionError, got %T", err)
+		}
+		if e.Name != "region" || err.Error() != "region is required" {
+			t.Errorf("Unexpected error: %#v", e)
+		}
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		fn := func(context.Context, *GetOpt, []string) error { return nil }
+		tests := []struct {
+			name   string
+			args   []string
+			expect *UnknownCommandError
+			msg    string
+		}{
+			{"command", []string{"lst"}, &UnknownCommandError{Name: "lst", Suggestions: []string{"list"}},
+				"not a command: 'lst', did you mean 'list'?"},
+			{"help", []string{"help", "lst"}, &UnknownCommandError{Name: "lst", Help: true, Suggestions: []string{"list"}},
+				"unkown help entry 'lst', did you mean 'list'?"},
+			{"option", []string{"--lst"}, &UnknownCommandError{Name: "--lst"},
+				"not a command or a valid option: '--lst'\n       Did you mean to pass it after the command?"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				buf := new(bytes.Buffer)
+				opt := New()
+				opt.Writer = buf
+				opt.NewCommand("list", "").SetCommandFn(fn)
+				opt.HelpCommand("")
+				err := opt.Dispatch(context.Background(), "help", tt.args)
+				var e *UnknownCommandError
+				if !errors.As(err, &e) {
+					t.Fatalf("Expected UnknownCommandError, got %T: %v", err, err)
+				}
+				if !reflect.DeepEqual(e, tt.expect) {
+					t.Errorf("Error didn't match expected value:\nexpected: %#v\ngot:      %#v", tt.expect, e)
+				}
+				if err.Error() != tt.msg {
+					t.Errorf("Error string didn't match expected value:\nexpected: %s\ngot:      %s", tt.msg, err)
+				}
+			})
+		}
+	})
+}
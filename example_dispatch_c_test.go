@@ -52,10 +52,11 @@ func ExampleGetOpt_Dispatch_cCommandHelp() {
 	//     go-getoptions.test list [--debug] [--help|-?] [--list-opt] [<args>]
 	//
 	// OPTIONS:
+	//     --list-opt    (default: false)
+	//
+	// GLOBAL OPTIONS:
 	//     --debug       (default: false)
 	//
 	//     --help|-?     (default: false)
 	//
-	//     --list-opt    (default: false)
-	//
 }
@@ -0,0 +1,263 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/DavidGamba/go-getoptions/option"
+)
+
+// Scalar - Types supported by the Opt and OptVar generic functions.
+type Scalar interface {
+	bool | string | int | int64 | uint | uint64 | float32 | float64 | time.Duration
+}
+
+// SliceElem - Types supported by the OptSlice and OptSliceVar generic functions.
+type SliceElem interface {
+	string | int | int64 | uint | uint64 | float32 | float64 | time.Duration
+}
+
+// OptVar - define an option of type T and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// The parser is chosen based on the type.
+// For example, the following are equivalent:
+//
+//     opt.IntVar(&i, "int", 5, opt.Alias("i"))
+//     getoptions.OptVar(opt, &i, "int", 5, opt.Alias("i"))
+//
+// Types without a dedicated method (int64, uint, uint64 and float32) are
+// handled through the Value interface.
+func OptVar[T Scalar](gopt *GetOpt, p *T, name string, def T, fns ...ModifyFn) {
+	switch p := any(p).(type) {
+	case *bool:
+		gopt.BoolVar(p, name, any(def).(bool), fns...)
+	case *string:
+		gopt.StringVar(p, name, any(def).(string), fns...)
+	case *int:
+		gopt.IntVar(p, name, any(def).(int), fns...)
+	case *float64:
+		gopt.Float64Var(p, name, any(def).(float64), fns...)
+	case *time.Duration:
+		gopt.DurationVar(p, name, any(def).(time.Duration), fns...)
+	default:
+		*p.(*T) = def
+		gopt.Var(&scalarValue[T]{p: p.(*T)}, name, fns...)
+	}
+}
+
+// Opt - define an option of type T and its aliases.
+// It returns a `*T` pointing to the variable holding the result.
+//
+// For example:
+//
+//     port := getoptions.Opt[uint](opt, "port", 8080)
+func Opt[T Scalar](gopt *GetOpt, name string, def T, fns ...ModifyFn) *T {
+	OptVar(gopt, &def, name, def, fns...)
+	return &def
+}
+
+// OptSliceVar - define a `[]T` option and its aliases.
+//
+// It follows the same min and max semantics as StringSliceVar.
+// The following are equivalent:
+//
+//     opt.IntSliceVar(&ii, "int", 1, 3)
+//     getoptions.OptSliceVar(opt, &ii, "int", 1, 3)
+func OptSliceVar[T SliceElem](gopt *GetOpt, p *[]T, name string, min, max int, fns ...ModifyFn) {
+	switch p := any(p).(type) {
+	case *[]string:
+		gopt.StringSliceVar(p, name, min, max, fns...)
+	case *[]int:
+		gopt.IntSliceVar(p, name, min, max, fns...)
+	case *[]time.Duration:
+		gopt.DurationSliceVar(p, name, min, max, fns...)
+	default:
+		gopt.sliceVar(&sliceValue[T]{p: p.(*[]T)}, name, min, max, fns...)
+	}
+}
+
+// OptSlice - define a `[]T` option and its aliases.
+// It returns a `*[]T` pointing to the variable holding the result.
+//
+// It follows the same min and max semantics as StringSlice.
+func OptSlice[T SliceElem](gopt *GetOpt, name string, min, max int, fns ...ModifyFn) *[]T {
+	s := []T{}
+	OptSliceVar(gopt, &s, name, min, max, fns...)
+	return &s
+}
+
+// OptMapVar - define a `map[string]string` option and its aliases.
+// Same as StringMapVar, provided for parity with OptVar and OptSliceVar.
+func OptMapVar(gopt *GetOpt, m *map[string]string, name string, min, max int, fns ...ModifyFn) {
+	gopt.StringMapVar(m, name, min, max, fns...)
+}
+
+// OptMap - define a `map[string]string` option and its aliases.
+// It returns a `*map[string]string` pointing to the variable holding the result.
+// Same as StringMap, provided for parity with Opt and OptSlice.
+func OptMap(gopt *GetOpt, name string, min, max int, fns ...ModifyFn) *map[string]string {
+	m := map[string]string{}
+	OptMapVar(gopt, &m, name, min, max, fns...)
+	return &m
+}
+
+// parseScalar - Converts the string into the type T.
+// Errors are reduced to the strconv reason, for example: "invalid syntax".
+func parseScalar[T SliceElem](s string) (T, error) {
+	var v T
+	var err error
+	switch p := any(&v).(type) {
+	case *string:
+		*p = s
+	case *int:
+		*p, err = strconv.Atoi(s)
+	case *int64:
+		*p, err = strconv.ParseInt(s, 10, 64)
+	case *uint:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, strconv.IntSize)
+		*p = uint(u)
+	case *uint64:
+		*p, err = strconv.ParseUint(s, 10, 64)
+	case *float32:
+		var f float64
+		f, err = strconv.ParseFloat(s, 32)
+		*p = float32(f)
+	case *float64:
+		*p, err = strconv.ParseFloat(s, 64)
+	case *time.Duration:
+		*p, err = time.ParseDuration(s)
+	}
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+	return v, err
+}
+
+func typeName[T any]() string {
+	var v T
+	return fmt.Sprintf("%T", v)
+}
+
+// scalarValue - Value implementation for single argument types without a dedicated method.
+type scalarValue[T Scalar] struct {
+	p *T
+}
+
+func (v *scalarValue[T]) Set(s string) error {
+	// Only SliceElem types are ever wrapped, bool is handled by BoolVar.
+	var err error
+	switch p := any(v.p).(type) {
+	case *int64:
+		*p, err = parseScalar[int64](s)
+	case *uint:
+		*p, err = parseScalar[uint](s)
+	case *uint64:
+		*p, err = parseScalar[uint64](s)
+	case *float32:
+		*p, err = parseScalar[float32](s)
+	}
+	return err
+}
+
+func (v *scalarValue[T]) String() string {
+	switch p := any(v.p).(type) {
+	case *float32:
+		return fmt.Sprintf("%f", *p)
+	}
+	return fmt.Sprintf("%v", *v.p)
+}
+
+func (v *scalarValue[T]) Type() string {
+	return typeName[T]()
+}
+
+func (v *scalarValue[T]) Get() interface{} {
+	return *v.p
+}
+
+func (v *scalarValue[T]) Clone() option.Value {
+	e := *v.p
+	return &scalarValue[T]{p: &e}
+}
+
+func (v *scalarValue[T]) Copy(from option.Value) error {
+	f, ok := from.(*scalarValue[T])
+	if !ok {
+		return v.Set(from.String())
+	}
+	*v.p = *f.p
+	return nil
+}
+
+// Clear - Single values are replaced on Set, there is nothing to clear.
+func (v *scalarValue[T]) Clear() {}
+
+// sliceValue - Value implementation for slice types without a dedicated method.
+type sliceValue[T SliceElem] struct {
+	p *[]T
+}
+
+func (v *sliceValue[T]) Set(s string) error {
+	e, err := parseScalar[T](s)
+	if err != nil {
+		return err
+	}
+	*v.p = append(*v.p, e)
+	return nil
+}
+
+func (v *sliceValue[T]) String() string {
+	return "[]"
+}
+
+func (v *sliceValue[T]) Type() string {
+	return typeName[T]()
+}
+
+func (v *sliceValue[T]) Get() interface{} {
+	return *v.p
+}
+
+func (v *sliceValue[T]) Clone() option.Value {
+	e := append([]T{}, *v.p...)
+	return &sliceValue[T]{p: &e}
+}
+
+func (v *sliceValue[T]) Copy(from option.Value) error {
+	if f, ok := from.(*sliceValue[T]); ok {
+		*v.p = append([]T{}, *f.p...)
+		return nil
+	}
+	// Other types are copied through the string representation of each element.
+	v.Clear()
+	if g, ok := from.(option.Getter); ok {
+		rv := reflect.ValueOf(g.Get())
+		if rv.Kind() == reflect.Slice {
+			for i := 0; i < rv.Len(); i++ {
+				if err := v.Set(fmt.Sprintf("%v", rv.Index(i).Interface())); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return v.Set(from.String())
+}
+
+func (v *sliceValue[T]) Clear() {
+	*v.p = []T{}
+}
@@ -0,0 +1,256 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+func TestOpt(t *testing.T) {
+	opt := New()
+	b := Opt(opt, "bool", false, opt.Alias("b"))
+	s := Opt(opt, "string", "default")
+	i := Opt(opt, "int", 1)
+	f := Opt(opt, "float64", 1.5)
+	i64 := Opt[int64](opt, "int64", 2)
+	u := Opt[uint](opt, "uint", 3)
+	u64 := Opt[uint64](opt, "uint64", 4)
+	f32 := Opt[float32](opt, "float32", 5)
+	var v uint
+	OptVar(opt, &v, "uintVar", 6)
+	_, err := opt.Parse([]string{
+		"-b",
+		"--string", "hello",
+		"--int", "123",
+		"--float64", "1.23",
+		"--int64=-9000000000",
+		"--uint", "7",
+		"--uint64=18000000000000000000",
+		"--float32", "0.5",
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if *b != true || *s != "hello" || *i != 123 || *f != 1.23 {
+		t.Errorf("Unexpected values: %v, %v, %v, %v", *b, *s, *i, *f)
+	}
+	if *i64 != -9000000000 || *u != 7 || *u64 != 18000000000000000000 || *f32 != 0.5 || v != 6 {
+		t.Errorf("Unexpected values: %v, %v, %v, %v, %v", *i64, *u, *u64, *f32, v)
+	}
+	if opt.Option("int").OptType != option.IntType {
+		t.Errorf("Unexpected type: %v", opt.Option("int").OptType)
+	}
+	if opt.Value("uint").(uint) != 7 {
+		t.Errorf("Unexpected value: %v", opt.Value("uint"))
+	}
+
+	tests := []struct {
+		name string
+		def  func(*GetOpt)
+		args []string
+		err  string
+	}{
+		{"uint", func(opt *GetOpt) { Opt[uint](opt, "uint", 0) }, []string{"--uint=-1"},
+			fmt.Sprintf(text.ErrorConvertToValue, "uint", "uint", "-1", "invalid syntax")},
+		{"int64", func(opt *GetOpt) { Opt[int64](opt, "int64", 0) }, []string{"--int64", "x"},
+			fmt.Sprintf(text.ErrorConvertToValue, "int64", "int64", "x", "invalid syntax")},
+		{"uint64", func(opt *GetOpt) { Opt[uint64](opt, "uint64", 0) }, []string{"--uint64", "99999999999999999999"},
+			fmt.Sprintf(text.ErrorConvertToValue, "uint64", "uint64", "99999999999999999999", "value out of range")},
+		{"float32", func(opt *GetOpt) { Opt[float32](opt, "float32", 0) }, []string{"--float32", "x"},
+			fmt.Sprintf(text.ErrorConvertToValue, "float32", "float32", "x", "invalid syntax")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := New()
+			tt.def(opt)
+			_, err := opt.Parse(tt.args)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+		})
+	}
+}
+
+func TestOptSlice(t *testing.T) {
+	opt := New()
+	ss := OptSlice[string](opt, "ss", 1, 2)
+	ii := OptSlice[int](opt, "ii", 1, 3)
+	uu := OptSlice[uint](opt, "uu", 1, 3)
+	var ff []float64
+	OptSliceVar(opt, &ff, "ff", 1, 1)
+	m := OptMap(opt, "m", 1, 1)
+	remaining, err := opt.Parse([]string{
+		"--ss", "a", "b",
+		"--ii", "1..3",
+		"--uu", "1", "2", "file",
+		"--uu", "3",
+		"--ff", "1.5",
+		"--m", "k=v",
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(remaining, []string{"file"}) {
+		t.Errorf("Unexpected remaining: %v", remaining)
+	}
+	if !reflect.DeepEqual(*ss, []string{"a", "b"}) || !reflect.DeepEqual(*ii, []int{1, 2, 3}) {
+		t.Errorf("Unexpected values: %v, %v", *ss, *ii)
+	}
+	if !reflect.DeepEqual(*uu, []uint{1, 2, 3}) || !reflect.DeepEqual(ff, []float64{1.5}) {
+		t.Errorf("Unexpected values: %v, %v", *uu, ff)
+	}
+	if !reflect.DeepEqual(*m, map[string]string{"k": "v"}) {
+		t.Errorf("Unexpected values: %v", *m)
+	}
+	if !reflect.DeepEqual(opt.Value("uu"), []uint{1, 2, 3}) {
+		t.Errorf("Unexpected value: %v", opt.Value("uu"))
+	}
+
+	t.Run("error", func(t *testing.T) {
+		opt := New()
+		OptSlice[uint](opt, "uu", 2, 3)
+		_, err := opt.Parse([]string{"--uu", "1", "x"})
+		expected := fmt.Sprintf(text.ErrorConvertToValue, "uu", "uint", "x", "invalid syntax")
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+
+	t.Run("set value", func(t *testing.T) {
+		opt := New()
+		n := Opt[int64](opt, "n", 1, opt.Max(10))
+		ids := OptSlice[int64](opt, "ids", 1, 99)
+		err := opt.SetValue("n", "50")
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorValueGreaterThanMax, "n", int64(50), 10) {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		if *n != 1 {
+			t.Errorf("Unexpected value: %v", *n)
+		}
+		err = opt.SetValue("ids", "1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.SetValue("ids", "7", "8")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*ids, []int64{7, 8}) {
+			t.Errorf("Unexpected value: %v", *ids)
+		}
+		err = opt.SetValue("ids", "9", "x")
+		if err == nil {
+			t.Errorf("Expected error")
+		}
+		if !reflect.DeepEqual(*ids, []int64{7, 8}) {
+			t.Errorf("Unexpected value: %v", *ids)
+		}
+	})
+
+	t.Run("env error", func(t *testing.T) {
+		t.Setenv("IDS", "1,x")
+		opt := New()
+		ids := OptSlice[int64](opt, "ids", 1, 99, opt.GetEnv("IDS"))
+		_, err := opt.Parse([]string{})
+		expected := fmt.Sprintf(text.ErrorConvertToValue, "IDS", "int64", "x", "invalid syntax")
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		if len(*ids) != 0 {
+			t.Errorf("Unexpected value: %v", *ids)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt := New()
+		OptSlice[uint](opt, "uu", 1, 1)
+		Opt[uint](opt, "u", 0)
+		got := opt.Help(HelpSynopsis, HelpOptionList)
+		expected := `SYNOPSIS:
+    go-getoptions.test [-u <uint>] [--uu <uint>]... [<args>]
+
+OPTIONS:
+    -u <uint>      (default: 0)
+
+    --uu <uint>    (default: [])
+
+`
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		for _, minMax := range [][]int{{0, 1}, {2, 1}} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("wrong min/max definition did not panic")
+					}
+				}()
+				opt := New()
+				OptSlice[uint](opt, "uu", minMax[0], minMax[1])
+			}()
+		}
+	})
+}
+
+func TestValueCopy(t *testing.T) {
+	t.Run("scalar", func(t *testing.T) {
+		var a, b int64 = 1, 2
+		v := &scalarValue[int64]{p: &a}
+		c := v.Clone()
+		v.Clear()
+		if c.Set("3") != nil || a != 1 {
+			t.Errorf("Unexpected value: %v", a)
+		}
+		if v.Copy(c) != nil || a != 3 {
+			t.Errorf("Unexpected value: %v", a)
+		}
+		if v.Copy(&scalarValue[int64]{p: &b}) != nil || a != 2 {
+			t.Errorf("Unexpected value: %v", a)
+		}
+		u := uint(4)
+		if v.Copy(&scalarValue[uint]{p: &u}) != nil || a != 4 {
+			t.Errorf("Unexpected value: %v", a)
+		}
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		a := []int64{1}
+		v := &sliceValue[int64]{p: &a}
+		c := v.Clone()
+		if c.Set("2") != nil || !reflect.DeepEqual(a, []int64{1}) {
+			t.Errorf("Unexpected value: %v", a)
+		}
+		if v.Copy(c) != nil || !reflect.DeepEqual(a, []int64{1, 2}) {
+			t.Errorf("Unexpected value: %v", a)
+		}
+		v.Clear()
+		if len(a) != 0 {
+			t.Errorf("Unexpected value: %v", a)
+		}
+		u := []uint{3, 4}
+		if v.Copy(&sliceValue[uint]{p: &u}) != nil || !reflect.DeepEqual(a, []int64{3, 4}) {
+			t.Errorf("Unexpected value: %v", a)
+		}
+		f := []float32{1.5}
+		if v.Copy(&sliceValue[float32]{p: &f}) == nil {
+			t.Errorf("Expected error")
+		}
+		if v.Copy(&scalarValue[uint]{p: &u[0]}) != nil || !reflect.DeepEqual(a, []int64{3}) {
+			t.Errorf("Unexpected value: %v", a)
+		}
+	})
+}
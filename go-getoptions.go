@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/DavidGamba/go-getoptions/completion"
 	"github.com/DavidGamba/go-getoptions/help"
@@ -52,6 +53,16 @@ const (
 	Pass
 )
 
+// PrefixMatching - Abbreviated option matching mode
+type PrefixMatching int
+
+// Abbreviated option matching modes
+const (
+	PrefixMatchingUniqueOnly PrefixMatching = iota
+	PrefixMatchingOn
+	PrefixMatchingOff
+)
+
 // HelpSection - Indicates what portion of the help to return.
 type HelpSection int
 
@@ -62,6 +73,7 @@ const (
 	HelpSynopsis
 	HelpCommandList
 	HelpOptionList
+	HelpArgumentList
 )
 
 // ErrorHelpCalled - Indicates the help has been handled.
@@ -74,6 +86,15 @@ var exitFn = os.Exit
 // Set as a variable to allow for easy testing.
 var completionWriter io.Writer = os.Stdout
 
+// CompletionShellEnvVar - Name of the environment variable that selects the completion output format.
+// When set to `zsh` or `fish`, completions are written with their descriptions,
+// see completion.ZshCompLineComplete and completion.FishCompLineComplete.
+// Otherwise the bash format, one completion per line, is used.
+var CompletionShellEnvVar = "GETOPTIONS_COMPLETION_SHELL"
+
+// ShowHiddenEnvVar - Name of the environment variable that shows hidden options and commands in the help when set to a non empty value.
+var ShowHiddenEnvVar = "GETOPTIONS_SHOW_HIDDEN"
+
 // GetOpt - main object.
 type GetOpt struct {
 	// Help fields
@@ -96,16 +117,38 @@ type GetOpt struct {
 	requireOrder   bool        // Stop parsing on non option
 	mapKeysToLower bool        // Set Map keys lower case
 
+	configUnknownMode UnknownMode    // Unknown configuration key mode
+	envPrefix         string         // Environment variable prefix
+	envListSeparator  string         // Environment variable list separator
+	caseInsensitive   bool           // Match options and commands ignoring case
+	prefixMatching    PrefixMatching // Abbreviated option matching mode
+	prefixMatchingSet bool           // Indicates if prefixMatching was set, otherwise it is inherited
+	abbrevWarn        bool           // Warn when an option is abbreviated
+	noSuggestions     bool           // Don't suggest similar names for unknown options and commands
+	hidden            bool           // Exclude the command from the help and completions
+	deprecated        bool           // Warn when the command is called
+	deprecatedMsg     string         // Deprecation message shown when the command is called
+	replacedBy        string         // Name of the command that replaces the deprecated command
+	groupOrder        []string       // Order of the option groups in the help
+	isHelpCommand     bool           // Defined with HelpCommand, excluded from the generated documentation
+
 	// Debugging
 	Writer io.Writer // io.Writer to write warnings to. Defaults to os.Stderr.
 
 	// Data
-	obj        map[string]*option.Option // indexed options
-	commands   map[string]*GetOpt
-	args       *argList
-	completion *completion.Node
+	obj         map[string]*option.Option // indexed options
+	groups      []help.Group              // option relationships
+	positionals []*option.Option          // positional arguments
+	argsLimits  []int                     // min and max amount of positional arguments
+	commands    map[string]*GetOpt
+	args        *argList
+	completion  *completion.Node
 }
 
+// Value - Interface implemented by custom option types.
+// See option.Value and the Var method.
+type Value = option.Value
+
 // ModifyFn - Function signature for functions that modify an option.
 type ModifyFn func(*option.Option)
 
@@ -127,6 +170,8 @@ func New() *GetOpt {
 		commands:   make(map[string]*GetOpt),
 		Writer:     os.Stderr,
 		completion: root,
+
+		configUnknownMode: Pass,
 	}
 	return gopt
 }
@@ -148,37 +193,80 @@ func (gopt *GetOpt) NewCommand(name string, description string) *GetOpt {
 	node := cmd.completion
 	node.Kind = completion.CommandNode
 	node.Name = cmd.name
+	node.Description = description
 	gopt.completion.AddChild(node)
 	gopt.commands[cmd.name] = cmd
 
 	return cmd
 }
 
+// SetHidden - Excludes the command from the help, the help command completions and the shell completions.
+// The command can still be called.
+// Set the environment variable named by ShowHiddenEnvVar to show it in the help.
+//
+// NOTE: Call before defining the HelpCommand.
+func (gopt *GetOpt) SetHidden() *GetOpt {
+	gopt.hidden = true
+	gopt.removeCompletionNode()
+	return gopt
+}
+
+// removeCompletionNode - Removes the command from the completions of its parent.
+func (gopt *GetOpt) removeCompletionNode() {
+	if gopt.parent == nil {
+		return
+	}
+	children := []*completion.Node{}
+	for _, node := range gopt.parent.completion.Children {
+		if node != gopt.completion {
+			children = append(children, node)
+		}
+	}
+	gopt.parent.completion.Children = children
+}
+
 // SetCommandFn - Defines the command entry point function.
 func (gopt *GetOpt) SetCommandFn(fn CommandFn) *GetOpt {
 	gopt.CommandFn = fn
 	return gopt
 }
 
-func (gopt *GetOpt) completionAppendAliases(aliases []string) {
+func (gopt *GetOpt) completionAppendAliases(opt *option.Option) {
+	if opt.IsHidden || opt.IsDeprecated {
+		return
+	}
 	node := gopt.completion.GetChildByName("options")
-	for _, alias := range aliases {
+	for _, alias := range opt.Aliases {
 		if len(alias) == 1 {
-			node.Entries = append(node.Entries, "-"+alias)
+			alias = "-" + alias
 		} else {
-			node.Entries = append(node.Entries, "--"+alias)
+			alias = "--" + alias
 		}
+		node.Entries = append(node.Entries, alias)
+		gopt.completionDescribe(alias, opt)
 	}
 }
 
-func (gopt *GetOpt) completionWithArgAppendAliases(aliases []string) {
+func (gopt *GetOpt) completionWithArgAppendAliases(opt *option.Option) {
+	if opt.IsHidden || opt.IsDeprecated {
+		return
+	}
 	node := gopt.completion.GetChildByName("options-with-arg")
-	for _, alias := range aliases {
+	for _, alias := range opt.Aliases {
 		if len(alias) == 1 {
-			node.Entries = append(node.Entries, "-"+alias)
+			alias = "-" + alias
 		} else {
-			node.Entries = append(node.Entries, "--"+alias)
+			alias = "--" + alias
 		}
+		node.Entries = append(node.Entries, alias)
+		gopt.completionDescribe(alias, opt)
+	}
+}
+
+// completionDescribe - Sets the option description as the completion description of the alias.
+func (gopt *GetOpt) completionDescribe(alias string, opt *option.Option) {
+	if opt.Description != "" {
+		gopt.completion.Descriptions[alias] = opt.Description
 	}
 }
 
@@ -220,50 +308,52 @@ func (gopt *GetOpt) Dispatch(ctx context.Context, helpCommandName string, args [
 	case helpCommandName:
 		if len(args) > 1 {
 			commandName := args[1]
-			for name, v := range gopt.commands {
-				if commandName == name {
-					fmt.Fprint(gopt.Writer, v.Help())
-					exitFn(1)
-					return nil
-				}
+			v, err := gopt.command(commandName)
+			if err != nil {
+				return err
+			}
+			if v != nil {
+				fmt.Fprint(gopt.Writer, v.Help())
+				exitFn(1)
+				return nil
 			}
-			// TODO: Expose string as var?
-			return fmt.Errorf("unkown help entry '%s'", commandName)
+			return &UnknownCommandError{Name: commandName, Help: true, Suggestions: gopt.commandSuggestions(commandName)}
 		}
 		fmt.Fprint(gopt.Writer, gopt.Help())
 		fmt.Fprint(gopt.Writer, gopt.extraDetails()+"\n")
 		exitFn(1)
 		return nil
 	default:
-		commandName := args[0]
-		for name, v := range gopt.commands {
-			if commandName == name {
-				if v.CommandFn != nil {
-					remaining, err := v.Parse(args[1:])
-					if len(v.commands) == 0 {
-						if v.Called(helpCommandName) {
-							fmt.Fprint(gopt.Writer, v.Help())
-							return ErrorHelpCalled
-						}
-					}
-					if err != nil {
-						return err
-					}
-					err = v.CommandFn(ctx, v, remaining)
-					if err != nil {
-						return err
+		v, err := gopt.command(args[0])
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			if v.deprecated {
+				fmt.Fprintf(gopt.Writer, "WARNING: %s\n", deprecationWarning(text.MessageOnDeprecatedCommand, v.name, v.replacedBy, v.deprecatedMsg))
+			}
+			if v.CommandFn != nil {
+				remaining, err := v.Parse(args[1:])
+				if len(v.commands) == 0 {
+					if v.Called(helpCommandName) {
+						fmt.Fprint(gopt.Writer, v.Help())
+						return ErrorHelpCalled
 					}
 				}
-				return nil
+				if err != nil {
+					return err
+				}
+				err = v.CommandFn(ctx, v, remaining)
+				if err != nil {
+					return err
+				}
 			}
+			return nil
 		}
 		if strings.HasPrefix(args[0], "-") {
-			// TODO: Expose string as var?
-			return fmt.Errorf(`not a command or a valid option: '%s'
-       Did you mean to pass it after the command?`, args[0])
+			return &UnknownCommandError{Name: args[0]}
 		}
-		// TODO: Expose string as var?
-		return fmt.Errorf("not a command: '%s'", args[0])
+		return &UnknownCommandError{Name: args[0], Suggestions: gopt.commandSuggestions(args[0])}
 	}
 }
 
@@ -314,6 +404,57 @@ func (gopt *GetOpt) CalledAs(name string) string {
 	return ""
 }
 
+// Source - Returns where the option value came from and the detail of the source:
+// the alias used in the command line, the environment variable name or the configuration file and key.
+// For example:
+//
+//     source, detail := opt.Source("region")
+//     if source == option.EnvSource {
+//         fmt.Printf("region set from %s\n", detail)
+//     }
+//
+// If the `name` is an option that wasn't declared it will return option.DefaultSource.
+func (gopt *GetOpt) Source(name string) (option.Source, string) {
+	if v, ok := gopt.obj[name]; ok {
+		return v.Source, v.UsedAlias
+	}
+	return option.DefaultSource, ""
+}
+
+// SetValue - Sets the option value in code, as if the given arguments were passed in the command line.
+// Slice and map options have their previous values replaced.
+// The option source is set to option.ProgrammaticSource.
+// On error, the option keeps its previous value and source.
+//
+// When called before Parse, command line options still take precedence.
+func (gopt *GetOpt) SetValue(name string, values ...string) error {
+	gopt.failIfUndefined([]string{name})
+	var opt *option.Option
+	for g := gopt; opt == nil; g = g.parent {
+		opt = g.obj[name]
+	}
+	opt.MapKeysToLower = gopt.mapKeysToLower
+	// Save into a copy so the option keeps its value and source on error.
+	c := opt.Clone()
+	c.ClearValues()
+	c.SetCalledFrom(option.ProgrammaticSource, name)
+	for _, v := range values {
+		err := c.Save(v)
+		if err != nil {
+			return err
+		}
+	}
+	err := c.Validate()
+	if err != nil {
+		return err
+	}
+	err = opt.CopyValue(c)
+	if err == nil {
+		opt.SetCalledFrom(option.ProgrammaticSource, name)
+	}
+	return err
+}
+
 // Value - Returns the value of the given option.
 //
 // Type assertions are required in cases where the compiler can't determine the type by context.
@@ -340,12 +481,26 @@ func (gopt *GetOpt) setOption(opts ...*option.Option) *GetOpt {
 	nodeWithArg := gopt.completion.GetChildByName("options-with-arg")
 	for _, opt := range opts {
 		gopt.obj[opt.Name] = opt
+		gopt.bindEnv(opt)
+		if opt.IsHidden || opt.IsDeprecated {
+			continue
+		}
 		if opt.OptType == option.BoolType {
 			// TODO: Add aliases
 			node.Entries = append(node.Entries, opt.Name)
 		} else {
 			nodeWithArg.Entries = append(nodeWithArg.Entries, opt.Name)
 		}
+		if len(opt.ValidValues) > 0 {
+			for _, alias := range opt.Aliases {
+				if len(alias) == 1 {
+					alias = "-" + alias
+				} else {
+					alias = "--" + alias
+				}
+				nodeWithArg.AddChild(completion.NewNode(alias, completion.CustomNode, opt.ValidValues))
+			}
+		}
 	}
 	return gopt
 }
@@ -439,6 +594,122 @@ func (gopt *GetOpt) SetMapKeysToLower() *GetOpt {
 	return gopt
 }
 
+// SetCaseInsensitive - Match options, including lazy matching, and command names ignoring case.
+// For example, `--Profile`, `--PROFILE` and `--pro` all match the `profile` option.
+// Exact matches take precedence, when several options match ignoring case an ambiguous option error is returned.
+//
+// CalledAs returns the alias as typed by the user.
+// Commands inherit the setting of their parent.
+func (gopt *GetOpt) SetCaseInsensitive() *GetOpt {
+	gopt.caseInsensitive = true
+	return gopt
+}
+
+func (gopt *GetOpt) isCaseInsensitive() bool {
+	for g := gopt; g != nil; g = g.parent {
+		if g.caseInsensitive {
+			return true
+		}
+	}
+	return false
+}
+
+// aliasHasPrefix - Same as strings.HasPrefix but ignoring case when SetCaseInsensitive is set.
+func (gopt *GetOpt) aliasHasPrefix(alias, prefix string) bool {
+	if gopt.isCaseInsensitive() {
+		return len(alias) >= len(prefix) && strings.EqualFold(alias[:len(prefix)], prefix)
+	}
+	return strings.HasPrefix(alias, prefix)
+}
+
+// aliasEqual - Compares the aliases ignoring case when SetCaseInsensitive is set.
+func (gopt *GetOpt) aliasEqual(alias, s string) bool {
+	if gopt.isCaseInsensitive() {
+		return strings.EqualFold(alias, s)
+	}
+	return alias == s
+}
+
+// setCompletionIgnoreCase - Sets the completion IgnoreCase of the command and its children.
+func (gopt *GetOpt) setCompletionIgnoreCase() {
+	gopt.completion.SetIgnoreCase(gopt.isCaseInsensitive())
+	for _, cmd := range gopt.commands {
+		cmd.setCompletionIgnoreCase()
+	}
+}
+
+// SetPrefixMatching - Determines how abbreviated options are matched.
+// For example, with a `--profile` option, `--pro` is an abbreviation.
+//
+// • PrefixMatchingUniqueOnly (default) accepts an abbreviation when it matches a single option,
+// considering the options of the command and of its child commands.
+// Otherwise an ambiguous option error is returned.
+//
+// • PrefixMatchingOn also accepts an abbreviation that matches a single option of the command
+// when it matches options of the child commands as well.
+// This is the only difference with PrefixMatchingUniqueOnly, for example,
+// with a `--verbose` option and a child command with a `--version` option,
+// `--ver` calls `--verbose` with PrefixMatchingOn and is ambiguous with PrefixMatchingUniqueOnly.
+// An abbreviation that matches several options of the command is ambiguous in both modes.
+//
+// • PrefixMatchingOff only accepts full aliases.
+//
+// Commands inherit the mode of their parent.
+// Use the NoAbbrev modify function to disable abbreviations for a single option.
+func (gopt *GetOpt) SetPrefixMatching(mode PrefixMatching) *GetOpt {
+	gopt.prefixMatching = mode
+	gopt.prefixMatchingSet = true
+	return gopt
+}
+
+// SetAbbreviationWarnings - Writes a warning to gopt.Writer every time an abbreviated option is used.
+// Useful to find and fix abbreviated options in scripts before disabling them with SetPrefixMatching.
+// Commands inherit the setting of their parent.
+func (gopt *GetOpt) SetAbbreviationWarnings() *GetOpt {
+	gopt.abbrevWarn = true
+	return gopt
+}
+
+func (gopt *GetOpt) prefixMatchingMode() PrefixMatching {
+	for g := gopt; g != nil; g = g.parent {
+		if g.prefixMatchingSet {
+			return g.prefixMatching
+		}
+	}
+	return PrefixMatchingUniqueOnly
+}
+
+func (gopt *GetOpt) isAbbrevWarn() bool {
+	for g := gopt; g != nil; g = g.parent {
+		if g.abbrevWarn {
+			return true
+		}
+	}
+	return false
+}
+
+// command - Returns the command that matches the name, nil if not found.
+// An exact match takes precedence, when ignoring case matches more than one command it returns an AmbiguousCommandError.
+func (gopt *GetOpt) command(name string) (*GetOpt, error) {
+	if cmd, ok := gopt.commands[name]; ok {
+		return cmd, nil
+	}
+	matches := []string{}
+	for n := range gopt.commands {
+		if gopt.aliasEqual(n, name) {
+			matches = append(matches, n)
+		}
+	}
+	if len(matches) > 1 {
+		sort.Strings(matches)
+		return nil, &AmbiguousCommandError{Name: name, Candidates: matches}
+	}
+	if len(matches) == 1 {
+		return gopt.commands[matches[0]], nil
+	}
+	return nil, nil
+}
+
 // Alias - Adds aliases to an option.
 func (gopt *GetOpt) Alias(alias ...string) ModifyFn {
 	gopt.failIfDefined(alias)
@@ -460,10 +731,131 @@ func (gopt *GetOpt) Required(msg ...string) ModifyFn {
 	}
 }
 
+// RequiredIf - Automatically return an error if any of the given options is
+// called but this option is not.
+// For example, `--password` is required when `--user` is used:
+//
+//     opt.String("password", "", opt.RequiredIf("user"))
+func (gopt *GetOpt) RequiredIf(names ...string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.RequiredIf = append(opt.RequiredIf, names...)
+	}
+}
+
+// MutuallyExclusive - Automatically return an error if more than one of the
+// given options is called.
+// The options are shown together in the synopsis: `[--json | --table]`.
+//
+// NOTE: Define after the options have been defined.
+func (gopt *GetOpt) MutuallyExclusive(names ...string) *GetOpt {
+	gopt.failIfUndefined(names)
+	gopt.groups = append(gopt.groups, help.Group{Kind: help.MutuallyExclusive, Names: names})
+	return gopt
+}
+
+// RequiredTogether - Automatically return an error if any of the given options
+// is called but not all of them are.
+// The options are shown together in the synopsis: `[--user <string> --password <string>]`.
+//
+// NOTE: Define after the options have been defined.
+func (gopt *GetOpt) RequiredTogether(names ...string) *GetOpt {
+	gopt.failIfUndefined(names)
+	gopt.groups = append(gopt.groups, help.Group{Kind: help.RequiredTogether, Names: names})
+	return gopt
+}
+
+// OneRequired - Automatically return an error if none of the given options is called.
+// Combine with MutuallyExclusive to require exactly one of them.
+// The options are shown together in the synopsis: `(--json | --table)`.
+//
+// NOTE: Define after the options have been defined.
+func (gopt *GetOpt) OneRequired(names ...string) *GetOpt {
+	gopt.failIfUndefined(names)
+	gopt.groups = append(gopt.groups, help.Group{Kind: help.OneRequired, Names: names})
+	return gopt
+}
+
+// failIfUndefined will *panic* if an option used in a group is not defined by
+// the command or its parents.
+// This is not an error because the programmer has to fix this!
+func (gopt *GetOpt) failIfUndefined(names []string) {
+	for _, name := range names {
+		found := false
+		for g := gopt; g != nil; g = g.parent {
+			if _, ok := g.obj[name]; ok {
+				found = true
+			}
+		}
+		if !found {
+			panic(fmt.Sprintf("Option '%s' is not defined", name))
+		}
+	}
+}
+
+// allGroups - Returns the option groups of the command and its parents.
+func (gopt *GetOpt) allGroups() []help.Group {
+	groups := []help.Group{}
+	for g := gopt; g != nil; g = g.parent {
+		groups = append(groups, g.groups...)
+	}
+	return groups
+}
+
+// checkGroups - Returns error if the option relationships are not satisfied.
+func (gopt *GetOpt) checkGroups() error {
+	// Sort the names so the reported error is deterministic
+	names := []string{}
+	for name := range gopt.obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opt := gopt.obj[name]
+		if opt.Called {
+			continue
+		}
+		for _, name := range opt.RequiredIf {
+			if other := gopt.Option(name); other != nil && other.Called {
+				return fmt.Errorf(text.ErrorOptionRequiresOption, other.UsedAlias, opt.Name)
+			}
+		}
+	}
+	for _, g := range gopt.allGroups() {
+		called := []*option.Option{}
+		for _, name := range g.Names {
+			if opt := gopt.Option(name); opt != nil && opt.Called {
+				called = append(called, opt)
+			}
+		}
+		switch g.Kind {
+		case help.MutuallyExclusive:
+			if len(called) > 1 {
+				return fmt.Errorf(text.ErrorMutuallyExclusiveOptions, called[0].UsedAlias, called[1].UsedAlias)
+			}
+		case help.RequiredTogether:
+			if len(called) > 0 && len(called) < len(g.Names) {
+				for _, name := range g.Names {
+					if !gopt.Called(name) {
+						return fmt.Errorf(text.ErrorOptionRequiresOption, called[0].UsedAlias, name)
+					}
+				}
+			}
+		case help.OneRequired:
+			if len(called) == 0 {
+				return fmt.Errorf(text.ErrorMissingOneOfRequiredOptions, g.Names)
+			}
+		}
+	}
+	return nil
+}
+
 // GetEnv - Will read an environment variable if set.
 // Precedence higher to lower: CLI option, environment variable, option default.
 //
-// Currently, only `opt.Bool`, `opt.BoolVar`, `opt.String`, and `opt.StringVar` are supported.
+// All option types are supported.
+// Slice and map options split the value using the list separator, `,` by default, see SetEnvListSeparator.
+// For example: `HOSTS=a,b,c` or `LABELS=k1=v1,k2=v2`.
+// Increment options take the counter value, for example: `VERBOSE=2`.
 //
 // When an environment variable that matches the variable from opt.GetEnv is
 // set, opt.GetEnv will set opt.Called(name) to true and will set
@@ -475,24 +867,214 @@ func (gopt *GetOpt) Required(msg ...string) ModifyFn {
 // "true" or "false" are valid.  They can be provided in any casing, for
 // example: "true", "True" or "TRUE".
 //
-// NOTE: Non supported option types behave with a No-Op when `opt.GetEnv` is defined.
+// NOTE: Parse returns the error of values that fail to convert to the option type, the option keeps its default value.
 func (gopt *GetOpt) GetEnv(name string) ModifyFn {
 	return func(opt *option.Option) {
+		// The value is read once all the modify functions have been applied, see bindEnv.
 		opt.SetEnvVar(name)
-		value := os.Getenv(name)
-		if value != "" {
-			switch opt.OptType {
-			case option.BoolType:
-				v := strings.ToLower(value)
-				if v == "true" || v == "false" {
-					opt.Save(v)
-					opt.SetCalled(name)
-				}
-			case option.StringType, option.IntType, option.Float64Type:
-				opt.Save(value)
-				opt.SetCalled(name)
-			}
+	}
+}
+
+// SetEnvPrefix - Reads every option from an environment variable named after the prefix and the option name.
+// Dashes in the option name are replaced with underscores and the name is upper cased.
+// Command options are nested under the command name.
+// For example, with prefix `MYAPP`:
+//
+//     --profile           MYAPP_PROFILE
+//     --dry-run           MYAPP_DRY_RUN
+//     deploy --region     MYAPP_DEPLOY_REGION
+//
+// Options with an explicit opt.GetEnv keep their environment variable.
+// The `help` option and hidden options are not read from the environment.
+// The environment variable names are shown in the help.
+// See GetEnv.
+func (gopt *GetOpt) SetEnvPrefix(prefix string) *GetOpt {
+	gopt.envPrefix = prefix
+	gopt.bindEnvPrefix()
+	return gopt
+}
+
+// SetEnvListSeparator - Sets the separator used to split environment variable values for slice and map options.
+// Defaults to `,`. Commands inherit the separator of their parent.
+//
+// NOTE: Call before defining the options that read environment variables.
+func (gopt *GetOpt) SetEnvListSeparator(sep string) *GetOpt {
+	if sep == "" {
+		panic("SetEnvListSeparator separator must not be empty!")
+	}
+	gopt.envListSeparator = sep
+	return gopt
+}
+
+// envPrefixName - Returns the environment variable prefix for the command, an empty string when not set.
+func (gopt *GetOpt) envPrefixName() string {
+	if gopt.envPrefix != "" {
+		return gopt.envPrefix
+	}
+	if gopt.parent == nil {
+		return ""
+	}
+	prefix := gopt.parent.envPrefixName()
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "_" + envName(gopt.name)
+}
+
+func (gopt *GetOpt) envSeparator() string {
+	if gopt.envListSeparator != "" {
+		return gopt.envListSeparator
+	}
+	if gopt.parent == nil {
+		return ","
+	}
+	return gopt.parent.envSeparator()
+}
+
+// bindEnvPrefix - Binds the options without an environment variable of the command and its children.
+func (gopt *GetOpt) bindEnvPrefix() {
+	names := []string{}
+	for name := range gopt.obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Options with an environment variable have already read it
+		if gopt.obj[name].EnvVar == "" {
+			gopt.bindEnv(gopt.obj[name])
+		}
+	}
+	for _, cmd := range gopt.commands {
+		cmd.bindEnvPrefix()
+	}
+}
+
+// bindEnv - Reads the option from its environment variable once the modify functions have been applied.
+// Options without an explicit environment variable read the variable named after the prefix,
+// the help option and hidden options are skipped.
+func (gopt *GetOpt) bindEnv(opt *option.Option) {
+	if opt.EnvVar != "" {
+		gopt.readEnv(opt, opt.EnvVar)
+		return
+	}
+	// TODO: "help" is hardcoded
+	if opt.Name == "help" || opt.IsHidden {
+		return
+	}
+	prefix := gopt.envPrefixName()
+	if prefix == "" {
+		return
+	}
+	gopt.readEnv(opt, prefix+"_"+envName(opt.Name))
+}
+
+// envName - Converts an option or command name into its environment variable form: dry-run -> DRY_RUN.
+func envName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+func (gopt *GetOpt) readEnv(opt *option.Option, name string) {
+	opt.SetEnvVar(name)
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	values := []string{value}
+	switch opt.OptType {
+	case option.BoolType:
+		v := strings.ToLower(value)
+		if v != "true" && v != "false" {
+			return
+		}
+		values = []string{v}
+	case option.StringRepeatType, option.IntRepeatType, option.DurationRepeatType, option.StringMapType:
+		values = strings.Split(value, gopt.envSeparator())
+	case option.ValueType:
+		if opt.MaxArgs > 0 {
+			values = strings.Split(value, gopt.envSeparator())
+		}
+	}
+	opt.MapKeysToLower = gopt.mapKeysToLower
+	// Save into a copy so a value that fails half way doesn't leave the option partially set.
+	c := opt.Clone()
+	c.UsedAlias = name
+	for _, v := range values {
+		if err := c.Save(v); err != nil {
+			opt.EnvErr = err
+			return
+		}
+	}
+	opt.EnvErr = opt.CopyValue(c)
+	if opt.EnvErr == nil {
+		opt.SetCalledFrom(option.EnvSource, name)
+	}
+}
+
+// ValidValues - Limits the arguments the option accepts to the given list.
+// Passing any other argument results in an error that lists the valid values.
+// The valid values are shown in the help and used for completion of `--option=`.
+//
+// Supported by `opt.String`, `opt.StringSlice` and their derivatives.
+// For `opt.StringMap` and its derivatives it limits the keys.
+func (gopt *GetOpt) ValidValues(values ...string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetValidValues(values...)
+	}
+}
+
+// Negatable - Allow a bool option to be negated.
+// For every long alias, an alias with the "no-" prefix is defined.
+// Optionally, provide the list of prefixes to use instead.
+//
+// When the option is negatable, the option always sets the value to true and
+// the negated alias always sets it to false, regardless of the default.
+// For example:
+//
+//     opt.BoolVar(&color, "color", true, opt.Negatable("no-", "no"))
+//
+// Accepts `--color`, `--no-color` and `--nocolor`.
+// The help shows the option as `--[no-]color`.
+func (gopt *GetOpt) Negatable(prefixes ...string) ModifyFn {
+	if len(prefixes) == 0 {
+		prefixes = []string{"no-"}
+	}
+	return func(opt *option.Option) {
+		if opt.OptType != option.BoolType {
+			panic(fmt.Sprintf("Negatable used on non bool option '%s'", opt.Name))
 		}
+		opt.NegationPrefixes = append(opt.NegationPrefixes, prefixes...)
+	}
+}
+
+// Secret - Redacts the option value in Dump.
+func (gopt *GetOpt) Secret() ModifyFn {
+	return func(opt *option.Option) {
+		opt.IsSecret = true
+	}
+}
+
+// Group - Lists the option in its own help section titled with the group name.
+// See SetGroupOrder.
+func (gopt *GetOpt) Group(name string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.Group = name
+	}
+}
+
+// Hidden - Excludes the option from the help and the shell completions.
+// The option can still be called.
+// Set the environment variable named by ShowHiddenEnvVar to show it in the help.
+func (gopt *GetOpt) Hidden() ModifyFn {
+	return func(opt *option.Option) {
+		opt.IsHidden = true
+	}
+}
+
+// NoAbbrev - The option only matches its full aliases, abbreviations are not accepted.
+// See SetPrefixMatching.
+func (gopt *GetOpt) NoAbbrev() ModifyFn {
+	return func(opt *option.Option) {
+		opt.NoAbbrev = true
 	}
 }
 
@@ -536,7 +1118,7 @@ func getCommandName(opt *GetOpt) string {
 func (gopt *GetOpt) Help(sections ...HelpSection) string {
 	if len(sections) == 0 {
 		// Print all in the following order
-		sections = []HelpSection{helpDefaultName, HelpSynopsis, HelpCommandList, HelpOptionList}
+		sections = []HelpSection{helpDefaultName, HelpSynopsis, HelpCommandList, HelpArgumentList, HelpOptionList}
 	}
 	helpTxt := ""
 	var scriptName string
@@ -556,37 +1138,110 @@ func (gopt *GetOpt) Help(sections ...HelpSection) string {
 			helpTxt += help.Name(scriptName, gopt.name, gopt.description)
 			helpTxt += "\n"
 		case HelpSynopsis:
-			options := []*option.Option{}
-			commands := []string{}
-			for _, option := range gopt.obj {
-				options = append(options, option)
-			}
-			for _, command := range gopt.commands {
-				commands = append(commands, command.name)
-			}
-			helpTxt += help.Synopsis(scriptName, gopt.name, gopt.synopsisArgs, options, commands)
+			helpTxt += gopt.helpSynopsis(scriptName)
 			helpTxt += "\n"
 		case HelpCommandList:
-			m := make(map[string]string)
-			for _, command := range gopt.commands {
-				m[command.name] = command.description
-			}
-			commands := help.CommandList(m)
+			commands := help.CommandList(gopt.helpCommandMap())
 			if commands != "" {
 				helpTxt += commands
 				helpTxt += "\n"
 			}
-		case HelpOptionList:
-			options := []*option.Option{}
-			for _, option := range gopt.obj {
-				options = append(options, option)
+		case HelpArgumentList:
+			args := help.ArgumentList(gopt.positionals)
+			if args != "" {
+				helpTxt += args
 			}
-			helpTxt += help.OptionList(options)
+		case HelpOptionList:
+			options, global := gopt.helpOptionsByOrigin()
+			helpTxt += help.OptionSections(options, global, gopt.helpGroupOrder())
 		}
 	}
 	return helpTxt
 }
 
+func (gopt *GetOpt) helpSynopsis(scriptName string) string {
+	commands := []string{}
+	for _, command := range gopt.helpCommands() {
+		commands = append(commands, command.name)
+	}
+	synopsisArgs := gopt.synopsisArgs
+	if synopsisArgs == "" {
+		synopsisArgs = help.ArgSynopsis(gopt.positionals)
+	}
+	return help.Synopsis(scriptName, gopt.name, synopsisArgs, gopt.helpOptions(), commands, gopt.allGroups()...)
+}
+
+// helpCommandMap - Returns the names and descriptions of the commands shown in the help.
+func (gopt *GetOpt) helpCommandMap() map[string]string {
+	m := make(map[string]string)
+	for _, command := range gopt.helpCommands() {
+		m[command.name] = command.description
+		if command.deprecated {
+			m[command.name] += " " + help.DeprecatedAnnotation(command.replacedBy)
+		}
+	}
+	return m
+}
+
+// helpOptionsByOrigin - Returns the options shown in the help split in the options defined in the command
+// and the global options, the ones inherited from the parent.
+func (gopt *GetOpt) helpOptionsByOrigin() (options, global []*option.Option) {
+	for _, opt := range gopt.helpOptions() {
+		if gopt.parent != nil && gopt.parent.obj[opt.Name] == opt {
+			global = append(global, opt)
+		} else {
+			options = append(options, opt)
+		}
+	}
+	return options, global
+}
+
+// SetGroupOrder - Sets the order of the option group sections in the help.
+// Groups that are not listed are shown after, sorted by name.
+// Commands inherit the order of their parent.
+// See Group.
+func (gopt *GetOpt) SetGroupOrder(names ...string) *GetOpt {
+	gopt.groupOrder = names
+	return gopt
+}
+
+func (gopt *GetOpt) helpGroupOrder() []string {
+	for g := gopt; g != nil; g = g.parent {
+		if g.groupOrder != nil {
+			return g.groupOrder
+		}
+	}
+	return nil
+}
+
+func showHidden() bool {
+	return os.Getenv(ShowHiddenEnvVar) != ""
+}
+
+// helpOptions - Returns the options shown in the help.
+func (gopt *GetOpt) helpOptions() []*option.Option {
+	options := []*option.Option{}
+	for _, opt := range gopt.obj {
+		if opt.IsHidden && !showHidden() {
+			continue
+		}
+		options = append(options, opt)
+	}
+	return options
+}
+
+// helpCommands - Returns the commands shown in the help.
+func (gopt *GetOpt) helpCommands() []*GetOpt {
+	commands := []*GetOpt{}
+	for _, command := range gopt.commands {
+		if command.hidden && !showHidden() {
+			continue
+		}
+		commands = append(commands, command)
+	}
+	return commands
+}
+
 // HelpCommand - Adds a help command with completion for all other commands.
 //
 // NOTE: Define after all other commands have been defined.
@@ -596,8 +1251,12 @@ func (gopt *GetOpt) HelpCommand(description string) *GetOpt {
 	}
 	// TODO: "help" is hardcoded
 	opt := gopt.NewCommand("help", description)
+	opt.isHelpCommand = true
 	commands := []string{}
-	for name := range gopt.commands {
+	for name, command := range gopt.commands {
+		if command.hidden || command.deprecated {
+			continue
+		}
 		commands = append(commands, name)
 	}
 	opt.CustomCompletion(commands)
@@ -622,7 +1281,10 @@ func (gopt *GetOpt) BoolVar(p *bool, name string, def bool, fns ...ModifyFn) {
 	for _, fn := range fns {
 		fn(opt)
 	}
-	gopt.completionAppendAliases(opt.Aliases)
+	if len(opt.NegationPrefixes) > 0 {
+		gopt.failIfDefined(opt.SetNegatable(opt.NegationPrefixes...))
+	}
+	gopt.completionAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -638,6 +1300,14 @@ func (gopt *GetOpt) handleBool(name string, argument string, usedAlias string) e
 	Debug.Println("handleBool")
 	opt := gopt.Option(name)
 	opt.SetCalled(usedAlias)
+	if len(opt.NegatedAliases) > 0 {
+		negated := false
+		for _, a := range opt.NegatedAliases {
+			negated = negated || a == usedAlias
+		}
+		opt.SetBool(!negated)
+		return nil
+	}
 	opt.SetBoolAsOppositeToDefault()
 	return nil
 }
@@ -647,24 +1317,30 @@ func (gopt *GetOpt) handleSingleOption(name string, argument string, usedAlias s
 	opt := gopt.Option(name)
 	opt.SetCalled(usedAlias)
 	if argument != "" {
-		return opt.Save(argument)
+		if err := opt.Save(argument); err != nil {
+			return err
+		}
+		return opt.Validate()
 	}
 	if !gopt.args.existsNext() {
 		Debug.Printf("handleSingleOption %v %v\n", gopt.args.remaining(), gopt.args.existsNext())
 		if opt.IsOptional {
 			return nil
 		}
-		return fmt.Errorf(text.ErrorMissingArgument, usedAlias)
+		return &MissingArgumentError{Name: name, Alias: usedAlias}
 	}
 	// Check if next arg is option
 	if optList, _ := isOption(gopt.args.peekNextValue(), gopt.mode); len(optList) > 0 {
 		if opt.IsOptional {
 			return nil
 		}
-		return fmt.Errorf(text.ErrorArgumentWithDash, usedAlias)
+		return &MissingArgumentError{Name: name, Alias: usedAlias, Arg: gopt.args.peekNextValue()}
 	}
 	gopt.args.next()
-	return opt.Save(gopt.args.value())
+	if err := opt.Save(gopt.args.value()); err != nil {
+		return err
+	}
+	return opt.Validate()
 }
 
 // StringVar - define a `string` option and its aliases.
@@ -681,7 +1357,7 @@ func (gopt *GetOpt) StringVar(p *string, name, def string, fns ...ModifyFn) {
 	for _, fn := range fns {
 		fn(opt)
 	}
-	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.completionWithArgAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -712,7 +1388,7 @@ func (gopt *GetOpt) StringVarOptional(p *string, name, def string, fns ...Modify
 	for _, fn := range fns {
 		fn(opt)
 	}
-	gopt.completionAppendAliases(opt.Aliases)
+	gopt.completionAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -739,7 +1415,7 @@ func (gopt *GetOpt) IntVar(p *int, name string, def int, fns ...ModifyFn) {
 	for _, fn := range fns {
 		fn(opt)
 	}
-	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.completionWithArgAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -769,7 +1445,7 @@ func (gopt *GetOpt) IntVarOptional(p *int, name string, def int, fns ...ModifyFn
 	for _, fn := range fns {
 		fn(opt)
 	}
-	gopt.completionAppendAliases(opt.Aliases)
+	gopt.completionAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -796,7 +1472,7 @@ func (gopt *GetOpt) Float64Var(p *float64, name string, def float64, fns ...Modi
 	for _, fn := range fns {
 		fn(opt)
 	}
-	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.completionWithArgAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -822,7 +1498,7 @@ func (gopt *GetOpt) Float64VarOptional(p *float64, name string, def float64, fns
 	for _, fn := range fns {
 		fn(opt)
 	}
-	gopt.completionAppendAliases(opt.Aliases)
+	gopt.completionAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -832,6 +1508,76 @@ func (gopt *GetOpt) Float64Optional(name string, def float64, fns ...ModifyFn) *
 	return &def
 }
 
+// DurationVar - define a `time.Duration` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// The argument is parsed with `time.ParseDuration`, for example: `--timeout 1m30s`.
+func (gopt *GetOpt) DurationVar(p *time.Duration, name string, def time.Duration, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.DurationType, p)
+	opt.SetDuration(def)
+	opt.DefaultStr = def.String()
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("duration")
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionWithArgAppendAliases(opt)
+	gopt.setOption(opt)
+}
+
+// Duration - define a `time.Duration` option and its aliases.
+//
+// The argument is parsed with `time.ParseDuration`, for example: `--timeout 1m30s`.
+func (gopt *GetOpt) Duration(name string, def time.Duration, fns ...ModifyFn) *time.Duration {
+	gopt.DurationVar(&def, name, def, fns...)
+	return &def
+}
+
+// TimeVar - define a `time.Time` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// The argument is parsed with `time.Parse` using the `time.RFC3339` layout by default.
+// Use the `TimeLayout` modify function to use a different layout.
+func (gopt *GetOpt) TimeVar(p *time.Time, name string, def time.Time, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.TimeType, p)
+	opt.SetTime(def)
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("time")
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	// The layout might have been updated by a modify function.
+	opt.DefaultStr = `""`
+	if !def.IsZero() {
+		opt.DefaultStr = def.Format(opt.TimeLayout)
+	}
+	gopt.completionWithArgAppendAliases(opt)
+	gopt.setOption(opt)
+}
+
+// Time - define a `time.Time` option and its aliases.
+//
+// The argument is parsed with `time.Parse` using the `time.RFC3339` layout by default.
+// Use the `TimeLayout` modify function to use a different layout.
+func (gopt *GetOpt) Time(name string, def time.Time, fns ...ModifyFn) *time.Time {
+	gopt.TimeVar(&def, name, def, fns...)
+	return &def
+}
+
+// TimeLayout - Sets the layout used to parse `time.Time` options.
+// For example:
+//
+//     opt.Time("date", time.Time{}, opt.TimeLayout("2006-01-02"))
+func (gopt *GetOpt) TimeLayout(layout string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetTimeLayout(layout)
+	}
+}
+
 // StringSliceVar - define a `[]string` option and its aliases.
 //
 // StringSliceVar will accept multiple calls to the same option and append them
@@ -864,7 +1610,7 @@ func (gopt *GetOpt) StringSliceVar(p *[]string, name string, min, max int, fns .
 		fn(opt)
 	}
 	Debug.Printf("StringMulti return: %v\n", *p)
-	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.completionWithArgAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -925,7 +1671,7 @@ func (gopt *GetOpt) IntSliceVar(p *[]int, name string, min, max int, fns ...Modi
 		fn(opt)
 	}
 	Debug.Printf("IntMulti return: %v\n", *p)
-	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.completionWithArgAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -954,6 +1700,48 @@ func (gopt *GetOpt) IntSlice(name string, min, max int, fns ...ModifyFn) *[]int
 	return &s
 }
 
+// DurationSliceVar - define a `[]time.Duration` option and its aliases.
+//
+// DurationSliceVar will accept multiple calls to the same option and append them
+// to the `[]time.Duration`.
+// For example, when called with `--wait 1s --wait 2m`, the value is `[]time.Duration{time.Second, 2 * time.Minute}`.
+//
+// It follows the same min and max semantics as StringSliceVar.
+func (gopt *GetOpt) DurationSliceVar(p *[]time.Duration, name string, min, max int, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.DurationRepeatType, p)
+	opt.DefaultStr = "[]"
+	opt.Handler = gopt.handleSliceMultiOption
+	opt.MinArgs = min
+	opt.MaxArgs = max
+	opt.SetHelpArgName("duration")
+	if min <= 0 {
+		panic(fmt.Sprintf("%s min should be > 0", name))
+	}
+	if max <= 0 || max < min {
+		panic(fmt.Sprintf("%s max should be > 0 and > min", name))
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+	Debug.Printf("DurationMulti return: %v\n", *p)
+	gopt.completionWithArgAppendAliases(opt)
+	gopt.setOption(opt)
+}
+
+// DurationSlice - define a `[]time.Duration` option and its aliases.
+//
+// DurationSlice will accept multiple calls to the same option and append them
+// to the `[]time.Duration`.
+// For example, when called with `--wait 1s --wait 2m`, the value is `[]time.Duration{time.Second, 2 * time.Minute}`.
+//
+// It follows the same min and max semantics as StringSlice.
+func (gopt *GetOpt) DurationSlice(name string, min, max int, fns ...ModifyFn) *[]time.Duration {
+	s := []time.Duration{}
+	gopt.DurationSliceVar(&s, name, min, max, fns...)
+	return &s
+}
+
 // StringMapVar - define a `map[string]string` option and its aliases.
 //
 // StringMapVar will accept multiple calls of `key=value` type to the same option
@@ -993,7 +1781,7 @@ func (gopt *GetOpt) StringMapVar(m *map[string]string, name string, min, max int
 		fn(opt)
 	}
 	Debug.Printf("StringMulti return: %v\n", *m)
-	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.completionWithArgAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -1018,13 +1806,23 @@ func (gopt *GetOpt) StringMap(name string, min, max int, fns ...ModifyFn) map[st
 	return m
 }
 
-// NOTE: Options that can be called multiple times and thus modify the used
-// alias, don't use usedAlias for their errors because the error is used to
-// check the min, max args.
-// TODO: Do a regex instead of matching the full error to enable usedAlias in errors.
 func (gopt *GetOpt) handleSliceMultiOption(name string, argument string, usedAlias string) error {
+	err := gopt.saveSliceMultiOption(name, argument, usedAlias)
+	if err != nil {
+		return err
+	}
+	return gopt.Option(name).Validate()
+}
+
+// NOTE: Options that can be called multiple times and thus modify the used
+// alias, use the option name for their errors.
+func (gopt *GetOpt) saveSliceMultiOption(name string, argument string, usedAlias string) error {
 	Debug.Printf("handleStringSlice\n")
 	opt := gopt.Option(name)
+	// Values set from an environment variable or a configuration file are replaced.
+	if opt.Called && opt.Source != option.CLISource {
+		opt.ClearValues()
+	}
 	opt.SetCalled(usedAlias)
 	opt.MapKeysToLower = gopt.mapKeysToLower
 	argCounter := 0
@@ -1041,14 +1839,14 @@ func (gopt *GetOpt) handleSliceMultiOption(name string, argument string, usedAli
 		Debug.Printf("total arguments: %d, index: %d, counter %d", gopt.args.size(), gopt.args.index(), argCounter)
 		if !gopt.args.existsNext() {
 			if required {
-				return fmt.Errorf(text.ErrorMissingArgument, name)
+				return &MissingArgumentError{Name: name, Alias: name}
 			}
-			return fmt.Errorf("NoMoreArguments")
+			return errNoMoreArguments
 		}
 		// Check if next arg is option
 		if optList, _ := isOption(gopt.args.peekNextValue(), gopt.mode); len(optList) > 0 {
 			Debug.Printf("Next arg is option: %s\n", gopt.args.peekNextValue())
-			return fmt.Errorf(text.ErrorArgumentWithDash, name)
+			return &MissingArgumentError{Name: name, Alias: name, Arg: gopt.args.peekNextValue()}
 		}
 		// Check if next arg is not key=value
 		if opt.OptType == option.StringMapType && !strings.Contains(gopt.args.peekNextValue(), "=") {
@@ -1063,6 +1861,20 @@ func (gopt *GetOpt) handleSliceMultiOption(name string, argument string, usedAli
 				return nil
 			}
 		}
+		if opt.OptType == option.DurationRepeatType {
+			_, err := time.ParseDuration(gopt.args.peekNextValue())
+			if !required && err != nil {
+				return nil
+			}
+		}
+		// Custom types can't be checked ahead, optional arguments that fail to convert are left alone.
+		if opt.OptType == option.ValueType && !required {
+			if err := opt.Save(gopt.args.peekNextValue()); err != nil {
+				return nil
+			}
+			gopt.args.next()
+			return nil
+		}
 		gopt.args.next()
 		return opt.Save(gopt.args.value())
 	}
@@ -1073,15 +1885,13 @@ func (gopt *GetOpt) handleSliceMultiOption(name string, argument string, usedAli
 		err := next(argCounter <= opt.MinArgs)
 		Debug.Printf("counter: %d, value: %v, err %v", argCounter, opt.Value(), err)
 		if err != nil {
-			if err.Error() == "NoMoreArguments" {
+			if err == errNoMoreArguments {
 				Debug.Printf("return value: %v", opt.Value())
 				return nil
 			}
 			// always fail if errors under min args
 			// After min args, skip missing arg errors
-			if argCounter <= opt.MinArgs ||
-				(err.Error() != fmt.Sprintf(text.ErrorMissingArgument, name) &&
-					err.Error() != fmt.Sprintf(text.ErrorArgumentWithDash, name)) {
+			if argCounter <= opt.MinArgs || !isMissingArgumentError(err) {
 				Debug.Printf("return value: %v, err: %v", opt.Value(), err)
 				return err
 			}
@@ -1103,7 +1913,7 @@ func (gopt *GetOpt) IncrementVar(p *int, name string, def int, fns ...ModifyFn)
 	for _, fn := range fns {
 		fn(opt)
 	}
-	gopt.completionAppendAliases(opt.Aliases)
+	gopt.completionAppendAliases(opt)
 	gopt.setOption(opt)
 }
 
@@ -1118,7 +1928,55 @@ func (gopt *GetOpt) handleIncrement(name string, argument string, usedAlias stri
 	opt := gopt.Option(name)
 	opt.SetCalled(usedAlias)
 	opt.SetInt(opt.Int() + 1)
-	return nil
+	return opt.Validate()
+}
+
+// Var - define an option of a custom type and its aliases.
+// The value must implement the Value interface.
+//
+// The result will be saved into the given Value by calling its `Set` method.
+// The help default is taken from the `String` method at definition time and
+// the help argument name from the `Type` method.
+// For example:
+//
+//     type level string
+//     func (l *level) Set(s string) error { ... }
+//     func (l *level) String() string     { return string(*l) }
+//     func (l *level) Type() string       { return "level" }
+//
+//     lvl := level("info")
+//     opt.Var(&lvl, "level", opt.Description("Log level"))
+func (gopt *GetOpt) Var(v Value, name string, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.ValueType, v)
+	opt.Handler = gopt.handleSingleOption
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionWithArgAppendAliases(opt)
+	gopt.setOption(opt)
+}
+
+// sliceVar - define an option of a custom type that accepts multiple arguments.
+// Each argument is passed to the Value's `Set` method.
+func (gopt *GetOpt) sliceVar(v Value, name string, min, max int, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.ValueType, v)
+	opt.Handler = gopt.handleSliceMultiOption
+	opt.MinArgs = min
+	opt.MaxArgs = max
+	if min <= 0 {
+		panic(fmt.Sprintf("%s min should be > 0", name))
+	}
+	if max <= 0 || max < min {
+		panic(fmt.Sprintf("%s max should be > 0 and > min", name))
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionWithArgAppendAliases(opt)
+	gopt.setOption(opt)
 }
 
 // func (opt *GetOpt) StringMulti(name string, def []string, min int, max int, fns ...ModifyFn) {}
@@ -1126,6 +1984,8 @@ func (gopt *GetOpt) handleIncrement(name string, argument string, usedAlias stri
 // func (opt *GetOpt) Procedure(name string, lambda_func int, fns ...ModifyFn) {}
 
 // Stringer - print a nice looking representation of the resulting `Option` map.
+//
+// Deprecated: Use Dump, it sorts the options, shows their sources and redacts secrets.
 func (gopt *GetOpt) Stringer() string {
 	s := "{\n"
 	for name, opt := range gopt.obj {
@@ -1142,8 +2002,10 @@ func (gopt *GetOpt) Stringer() string {
 	return s
 }
 
-// TODO: Add case insensitive matching.
-func (gopt *GetOpt) getOptionFromAliases(alias string) (optName, usedAlias string, found bool, err error) {
+// getOptionFromAliases - Returns the name of the option matching the given alias.
+// usedAlias is the full alias that matched and calledAs is the alias as typed by the user,
+// they only differ when matching ignoring case.
+func (gopt *GetOpt) getOptionFromAliases(alias string) (optName, usedAlias, calledAs string, found bool, err error) {
 	Debug.Printf("getOptionFromAliases: %s\n", gopt.name)
 
 	// Attempt to fully match node option
@@ -1156,18 +2018,43 @@ func (gopt *GetOpt) getOptionFromAliases(alias string) (optName, usedAlias strin
 				found = true
 				optName = name
 				usedAlias = v
+				calledAs = v
 				break
 			}
 		}
 	}
 
+	// Attempt to fully match node option ignoring case
+	if !found && gopt.isCaseInsensitive() {
+		names := []string{}
+		aliases := []string{}
+		for name, option := range gopt.obj {
+			for _, v := range option.Aliases {
+				if strings.EqualFold(v, alias) {
+					names = append(names, name)
+					aliases = append(aliases, v)
+				}
+			}
+		}
+		if len(names) >= 2 {
+			sort.Strings(aliases)
+			return optName, usedAlias, calledAs, found, &AmbiguousOptionError{Alias: alias, Candidates: aliases}
+		}
+		if len(names) == 1 {
+			found = true
+			optName = names[0]
+			usedAlias = aliases[0]
+			calledAs = alias
+		}
+	}
+
 	// Attempt to fully match command option
 	matches := []string{}
 	for _, command := range gopt.commands {
 		for name, option := range command.obj {
 			for _, v := range option.Aliases {
 				Debug.Printf("Trying to match '%s' against '%s' alias for command option '%s'\n", alias, v, name)
-				if v == alias {
+				if gopt.aliasEqual(v, alias) {
 					Debug.Printf("found: %s, %s\n", v, alias)
 					matches = append(matches, v)
 					continue
@@ -1179,20 +2066,25 @@ func (gopt *GetOpt) getOptionFromAliases(alias string) (optName, usedAlias strin
 	// If there are full matches of the command return with an empty match at the parent.
 	// There is no case in which a match could be found at the parent because aliases are checked.
 	if len(matches) >= 1 {
-		Debug.Printf("getOptionFromAliases return: %s, %s, %v\n", optName, usedAlias, found)
-		return optName, usedAlias, found, nil
+		Debug.Printf("getOptionFromAliases return: %s, %s, %s, %v\n", optName, usedAlias, calledAs, found)
+		return optName, usedAlias, calledAs, found, nil
 	}
 
 	// Attempt to match initial chars of node option
-	if !found {
+	mode := gopt.prefixMatchingMode()
+	if !found && mode != PrefixMatchingOff {
 		matches := []string{}
+		matchedAlias := ""
 		for name, option := range gopt.obj {
+			if option.NoAbbrev {
+				continue
+			}
 			for _, v := range option.Aliases {
 				Debug.Printf("Trying to lazy match '%s' against '%s' alias for '%s'\n", alias, v, name)
-				if strings.HasPrefix(v, alias) {
+				if gopt.aliasHasPrefix(v, alias) {
 					Debug.Printf("found: %s, %s\n", v, alias)
 					matches = append(matches, name)
-					usedAlias = v
+					matchedAlias = v
 					continue
 				}
 			}
@@ -1203,9 +2095,12 @@ func (gopt *GetOpt) getOptionFromAliases(alias string) (optName, usedAlias strin
 		commandMatches := []string{}
 		for _, command := range gopt.commands {
 			for name, option := range command.obj {
+				if option.NoAbbrev {
+					continue
+				}
 				for _, v := range option.Aliases {
 					Debug.Printf("Trying to lazy match '%s' against '%s' alias for command option '%s'\n", alias, v, name)
-					if strings.HasPrefix(v, alias) {
+					if gopt.aliasHasPrefix(v, alias) {
 						Debug.Printf("found: %s, %s\n", v, alias)
 						commandMatches = append(commandMatches, v)
 						continue
@@ -1230,17 +2125,29 @@ func (gopt *GetOpt) getOptionFromAliases(alias string) (optName, usedAlias strin
 		commandMatches = dedup(commandMatches)
 		combined := dedup(append(matches, commandMatches...))
 
+		if mode == PrefixMatchingOn && len(matches) == 1 {
+			combined = matches
+		}
 		if len(combined) >= 2 {
 			sort.Strings(combined)
-			return optName, usedAlias, found, fmt.Errorf(text.ErrorAmbiguousArgument, alias, combined)
+			return optName, usedAlias, calledAs, found, &AmbiguousOptionError{Alias: alias, Candidates: combined}
 		}
 		if len(matches) == 1 {
 			found = true
 			optName = matches[0]
+			usedAlias = matchedAlias
+			calledAs = matchedAlias
+			// Like full matches, record the alias as typed when ignoring case
+			if gopt.isCaseInsensitive() {
+				calledAs = alias
+			}
+			if gopt.isAbbrevWarn() {
+				fmt.Fprintf(gopt.Writer, "WARNING: "+text.MessageOnAbbreviation+"\n", alias, matchedAlias)
+			}
 		}
 	}
-	Debug.Printf("getOptionFromAliases return: %s, %s, %v\n", optName, usedAlias, found)
-	return optName, usedAlias, found, nil
+	Debug.Printf("getOptionFromAliases return: %s, %s, %s, %v\n", optName, usedAlias, calledAs, found)
+	return optName, usedAlias, calledAs, found, nil
 }
 
 // Parse - Call the parse method when done describing.
@@ -1256,7 +2163,20 @@ func (gopt *GetOpt) getOptionFromAliases(alias string) (optName, usedAlias strin
 //     remaining, err := opt.Parse(os.Args[1:])
 func (gopt *GetOpt) Parse(args []string) ([]string, error) {
 	gopt.passOptionsToChildren()
-	return gopt.parse(args)
+	// Only the top level Parse resets the deprecation warnings, the command Parse called from Dispatch shares the options.
+	err := gopt.checkDeprecated(gopt.parent == nil)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := gopt.parse(args)
+	if err != nil {
+		return nil, err
+	}
+	err = gopt.parseArgs(remaining)
+	if err != nil {
+		return nil, err
+	}
+	return remaining, nil
 }
 
 func (gopt *GetOpt) passOptionsToChildren() error {
@@ -1277,6 +2197,16 @@ func (gopt *GetOpt) passOptionsToChildren() error {
 			nodeWithArg := commandOpt.completion.GetChildByName("options-with-arg")
 			nodeWithArg.Entries = append(nodeWithArg.Entries, parentNodeWithArg.Entries...)
 		}
+		// pass option argument completions to child
+		parentNodeWithArg := gopt.completion.GetChildByName("options-with-arg")
+		nodeWithArg := commandOpt.completion.GetChildByName("options-with-arg")
+		for _, child := range parentNodeWithArg.Children {
+			nodeWithArg.AddChild(child)
+		}
+		// pass option completion descriptions to child
+		for entry, description := range gopt.completion.Descriptions {
+			commandOpt.completion.Descriptions[entry] = description
+		}
 		// Once we are done passing the options to the command, pass them along to its children.
 		commandOpt.passOptionsToChildren()
 	}
@@ -1295,7 +2225,15 @@ func (gopt *GetOpt) parse(args []string) ([]string, error) {
 	compLine := os.Getenv("COMP_LINE")
 	// https://stackoverflow.com/a/33396628
 	if compLine != "" {
-		fmt.Fprintln(completionWriter, strings.Join(gopt.completion.CompLineComplete(false, compLine), "\n"))
+		gopt.setCompletionIgnoreCase()
+		switch os.Getenv(CompletionShellEnvVar) {
+		case "zsh":
+			fmt.Fprintln(completionWriter, strings.Join(gopt.completion.ZshCompLineComplete(compLine), "\n"))
+		case "fish":
+			fmt.Fprintln(completionWriter, strings.Join(gopt.completion.FishCompLineComplete(compLine), "\n"))
+		default:
+			fmt.Fprintln(completionWriter, strings.Join(gopt.completion.CompLineComplete(false, compLine), "\n"))
+		}
 		exitFn(124) // programmable completion restarts from the beginning, with an attempt to find a new compspec for that command.
 	}
 	al := newArgList(args)
@@ -1322,7 +2260,7 @@ func (gopt *GetOpt) parse(args []string) ([]string, error) {
 			Debug.Printf("Parse continue\n")
 			for _, optElement := range optList {
 				Debug.Printf("Parse optElement: %s\n", optElement)
-				optName, usedAlias, ok, err := gopt.getOptionFromAliases(optElement)
+				optName, usedAlias, calledAs, ok, err := gopt.getOptionFromAliases(optElement)
 				if err != nil {
 					return nil, err
 				}
@@ -1333,6 +2271,8 @@ func (gopt *GetOpt) parse(args []string) ([]string, error) {
 					handler := opt.Handler
 					Debug.Printf("handler found: name %s, argument %s, index %d, list %s, args %v\n", optName, argument, gopt.args.index(), optList[0], gopt.args.remaining())
 					err := handler(optName, argument, usedAlias)
+					// Report the alias as typed, the handler needs the full alias to tell negated forms apart
+					opt.UsedAlias = calledAs
 					if err != nil {
 						Debug.Printf("handler return: value %v, return %v, %v", opt.Value(), nil, err)
 						return nil, err
@@ -1350,10 +2290,11 @@ func (gopt *GetOpt) parse(args []string) ([]string, error) {
 						remaining = append(remaining, arg)
 					case Warn:
 						// TODO: This WARNING can't be changed into another language. Hardcoded.
-						fmt.Fprintf(gopt.Writer, "WARNING: "+text.MessageOnUnknown+"\n", optElement)
+						err := &UnknownOptionError{Alias: optElement, Dash: optionDash(arg), Suggestions: gopt.optionSuggestions(optElement)}
+						fmt.Fprintf(gopt.Writer, "WARNING: %s\n", err)
 						remaining = append(remaining, arg)
 					default:
-						err := fmt.Errorf(text.MessageOnUnknown, optElement)
+						err := &UnknownOptionError{Alias: optElement, Dash: optionDash(arg), Suggestions: gopt.optionSuggestions(optElement)}
 						Debug.Printf("return %v, %v", nil, err)
 						return nil, err
 					}
@@ -1369,14 +2310,48 @@ func (gopt *GetOpt) parse(args []string) ([]string, error) {
 			remaining = append(remaining, arg)
 		}
 	}
-	// After parsing all options, verify that all required options where called.
-	for _, option := range gopt.obj {
-		err := option.CheckRequired()
+	err := gopt.handleDeprecated()
+	if err != nil {
+		Debug.Printf("return %v, %v", nil, err)
+		return nil, err
+	}
+	// After parsing all options, return errors and validate values read from environment variables
+	// and verify that all required options where called.
+	// Sort the names so the reported error is deterministic.
+	names := []string{}
+	for name := range gopt.obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opt := gopt.obj[name]
+		if opt.EnvErr != nil && opt.Source != option.CLISource {
+			Debug.Printf("return %v, %v", nil, opt.EnvErr)
+			return nil, opt.EnvErr
+		}
+		if opt.Called && opt.Source == option.EnvSource {
+			err := opt.Validate()
+			if err != nil {
+				Debug.Printf("return %v, %v", nil, err)
+				return nil, err
+			}
+		}
+		err = opt.CheckLength()
+		if err != nil {
+			Debug.Printf("return %v, %v", nil, err)
+			return nil, err
+		}
+		err = opt.CheckRequired()
 		if err != nil {
 			Debug.Printf("return %v, %v", nil, err)
 			return nil, err
 		}
 	}
+	err = gopt.checkGroups()
+	if err != nil {
+		Debug.Printf("return %v, %v", nil, err)
+		return nil, err
+	}
 	Debug.Printf("return %v, %v", remaining, nil)
 	return remaining, nil
 }
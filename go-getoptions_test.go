@@ -15,6 +15,8 @@ import (
 	"io/ioutil"
 	"os"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -516,6 +518,154 @@ func TestGetOptBool(t *testing.T) {
 	}
 }
 
+func TestNegatable(t *testing.T) {
+	tests := []struct {
+		name     string
+		def      bool
+		prefixes []string
+		args     []string
+		value    bool
+		calledAs string
+	}{
+		{"not called", true, nil, []string{}, true, ""},
+		{"called with default true", true, nil, []string{"--color"}, true, "color"},
+		{"called with default false", false, nil, []string{"--color"}, true, "color"},
+		{"negated with default true", true, nil, []string{"--no-color"}, false, "no-color"},
+		{"negated with default false", false, nil, []string{"--no-color"}, false, "no-color"},
+		{"last one wins", false, nil, []string{"--no-color", "--color"}, true, "color"},
+		{"alias", false, nil, []string{"-c"}, true, "c"},
+		{"negated long alias", true, nil, []string{"--no-colour"}, false, "no-colour"},
+		{"prefixes", true, []string{"no-", "no"}, []string{"--nocolor"}, false, "nocolor"},
+		{"prefixes dash", true, []string{"no-", "no"}, []string{"--no-colour"}, false, "no-colour"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var color bool
+			opt := New()
+			opt.BoolVar(&color, "color", tt.def, opt.Alias("c", "colour"), opt.Negatable(tt.prefixes...))
+			_, err := opt.Parse(tt.args)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if color != tt.value {
+				t.Errorf("Wrong value: %v != %v", color, tt.value)
+			}
+			if opt.CalledAs("color") != tt.calledAs {
+				t.Errorf("Wrong called as: %v != %v", opt.CalledAs("color"), tt.calledAs)
+			}
+		})
+	}
+
+	t.Run("env", func(t *testing.T) {
+		os.Setenv("COLOR", "false")
+		defer os.Unsetenv("COLOR")
+		opt := New()
+		color := opt.Bool("color", true, opt.Negatable(), opt.GetEnv("COLOR"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *color != false {
+			t.Errorf("Wrong value: %v", *color)
+		}
+	})
+
+	t.Run("short alias prefix of negated alias", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			args  []string
+			value bool
+		}{
+			{"alias", []string{"-n"}, true},
+			{"negated", []string{"--no-dry-run"}, false},
+			{"lazy negated", []string{"--no-d"}, false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				opt := New()
+				dryRun := opt.Bool("dry-run", false, opt.Alias("n"), opt.Negatable())
+				_, err := opt.Parse(tt.args)
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				if *dryRun != tt.value {
+					t.Errorf("Wrong value: %v != %v", *dryRun, tt.value)
+				}
+			})
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt := New()
+		opt.Bool("color", true, opt.Alias("c"), opt.Negatable("no-", "no"), opt.Description("Colorize output"))
+		opt.Bool("quiet", false, opt.Required())
+		got := opt.Help(HelpSynopsis, HelpOptionList)
+		expected := `SYNOPSIS:
+    go-getoptions.test --quiet [--[no-]color|-c] [<args>]
+
+REQUIRED PARAMETERS:
+    --quiet
+
+OPTIONS:
+    --[no-]color|-c    Colorize output (default: true)
+
+`
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		exitFn = func(code int) {}
+		defer func() { exitFn = os.Exit }()
+		opt := New()
+		opt.Bool("color", true, opt.Negatable())
+		buf := new(bytes.Buffer)
+		completionWriter = buf
+		defer func() { completionWriter = os.Stdout; os.Setenv("COMP_LINE", "") }()
+		os.Setenv("COMP_LINE", "test --")
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if buf.String() != "--color\n--no-color\n" {
+			t.Errorf("Unexpected completion: %q", buf.String())
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		tests := []struct {
+			name string
+			fn   func()
+		}{
+			{"non bool", func() {
+				opt := New()
+				opt.String("color", "", opt.Negatable())
+			}},
+			{"negated alias defined", func() {
+				opt := New()
+				opt.Bool("no-color", false)
+				opt.Bool("color", true, opt.Negatable())
+			}},
+			{"alias of negated option", func() {
+				opt := New()
+				opt.Bool("color", true, opt.Negatable())
+				opt.Bool("no-color", false)
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("%s did not panic", tt.name)
+					}
+				}()
+				tt.fn()
+			})
+		}
+	})
+}
+
 func TestCalled(t *testing.T) {
 	opt := New()
 	opt.Bool("hello", false)
@@ -1725,6 +1875,347 @@ func TestIncrement(t *testing.T) {
 	}
 }
 
+func TestDuration(t *testing.T) {
+	opt := New()
+	var d time.Duration
+	opt.DurationVar(&d, "d", time.Second, opt.Alias("t"))
+	d2 := opt.Duration("d2", 0)
+	ds := opt.DurationSlice("ds", 1, 3)
+	remaining, err := opt.Parse([]string{
+		"-t", "1m30s",
+		"--ds", "1s", "2ms", "file",
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if d != 90*time.Second || *d2 != 0 {
+		t.Errorf("Unexpected value: %v, %v", d, *d2)
+	}
+	if !reflect.DeepEqual(*ds, []time.Duration{time.Second, 2 * time.Millisecond}) {
+		t.Errorf("Unexpected value: %v", *ds)
+	}
+	if !reflect.DeepEqual(remaining, []string{"file"}) {
+		t.Errorf("Unexpected remaining: %v", remaining)
+	}
+
+	opt = New()
+	opt.Duration("d", 0)
+	_, err = opt.Parse([]string{"--d", "5"})
+	if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToDuration, "d", "5") {
+		t.Errorf("Error string didn't match expected value: %v", err)
+	}
+
+	opt = New()
+	opt.DurationSlice("ds", 2, 3)
+	_, err = opt.Parse([]string{"--ds", "1s", "x"})
+	if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToDuration, "ds", "x") {
+		t.Errorf("Error string didn't match expected value: %v", err)
+	}
+
+	opt = New()
+	opt.Duration("timeout", 5*time.Second)
+	opt.DurationSlice("wait", 1, 1, opt.Required())
+	got := opt.Help(HelpSynopsis, HelpOptionList)
+	expected := `SYNOPSIS:
+    go-getoptions.test <--wait <duration>>... [--timeout <duration>] [<args>]
+
+REQUIRED PARAMETERS:
+    --wait <duration>
+
+OPTIONS:
+    --timeout <duration>    (default: 5s)
+
+`
+	if got != expected {
+		t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+	}
+}
+
+func TestTime(t *testing.T) {
+	opt := New()
+	var tm time.Time
+	opt.TimeVar(&tm, "since", time.Time{})
+	day := opt.Time("day", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), opt.TimeLayout("2006-01-02"))
+	_, err := opt.Parse([]string{
+		"--since", "2021-02-03T04:05:06Z",
+		"--day=2021-02-03",
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if !tm.Equal(time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)) {
+		t.Errorf("Unexpected value: %v", tm)
+	}
+	if !day.Equal(time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected value: %v", *day)
+	}
+
+	_, err = opt.Parse([]string{"--day", "2021"})
+	if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToTime, "day", "2021", "2006-01-02") {
+		t.Errorf("Error string didn't match expected value: %v", err)
+	}
+
+	opt = New()
+	opt.Time("since", time.Time{})
+	opt.Time("day", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), opt.TimeLayout("2006-01-02"))
+	got := opt.Help(HelpOptionList)
+	expected := `OPTIONS:
+    --day <time>      (default: 2020-01-01)
+
+    --since <time>    (default: "")
+
+`
+	if got != expected {
+		t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+	}
+}
+
+func TestValidValues(t *testing.T) {
+	opt := New()
+	format := opt.String("format", "json", opt.ValidValues("json", "yaml", "table"), opt.Alias("f"))
+	ss := opt.StringSlice("ss", 1, 2, opt.ValidValues("a", "b"))
+	m := opt.StringMap("m", 1, 1, opt.ValidValues("k1", "k2"))
+	_, err := opt.Parse([]string{"-f", "yaml", "--ss", "a", "b", "--m", "k2=v"})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if *format != "yaml" || !reflect.DeepEqual(*ss, []string{"a", "b"}) || !reflect.DeepEqual(m, map[string]string{"k2": "v"}) {
+		t.Errorf("Unexpected values: %v, %v, %v", *format, *ss, m)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"string", []string{"-f", "xml"}, fmt.Sprintf(text.ErrorArgumentNotValidValue, "f", "xml", []string{"json", "yaml", "table"})},
+		{"slice", []string{"--ss", "c"}, fmt.Sprintf(text.ErrorArgumentNotValidValue, "ss", "c", []string{"a", "b"})},
+		{"map", []string{"--m", "k3=v"}, fmt.Sprintf(text.ErrorArgumentNotValidValue, "m", "k3", []string{"k1", "k2"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := opt.Parse(tt.args)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+		})
+	}
+
+	t.Run("completion", func(t *testing.T) {
+		called := false
+		exitFn = func(code int) { called = true }
+		defer func() { exitFn = os.Exit }()
+		opt := New()
+		opt.String("format", "json", opt.ValidValues("json", "yaml", "table"))
+		opt.NewCommand("show", "")
+		buf := new(bytes.Buffer)
+		completionWriter = buf
+		defer func() { completionWriter = os.Stdout; os.Setenv("COMP_LINE", "") }()
+		os.Setenv("COMP_LINE", "test show --format=")
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !called {
+			t.Errorf("COMP_LINE set and exit wasn't called")
+		}
+		if buf.String() != "json\ntable\nyaml\n" {
+			t.Errorf("Unexpected completion: %q", buf.String())
+		}
+	})
+}
+
+func TestOptionGroups(t *testing.T) {
+	define := func() *GetOpt {
+		opt := New()
+		opt.Bool("json", false, opt.Alias("j"))
+		opt.Bool("table", false)
+		opt.Bool("yaml", false)
+		opt.String("user", "")
+		opt.String("password", "", opt.Alias("p"))
+		opt.String("token", "", opt.RequiredIf("user", "password"))
+		opt.MutuallyExclusive("json", "table", "yaml")
+		opt.OneRequired("json", "table", "yaml")
+		opt.RequiredTogether("user", "password")
+		return opt
+	}
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"ok", []string{"--json"}, ""},
+		{"ok together", []string{"--table", "--user", "u", "-p", "p", "--token", "t"}, ""},
+		{"exclusive", []string{"-j", "--table"}, fmt.Sprintf(text.ErrorMutuallyExclusiveOptions, "j", "table")},
+		{"one required", []string{}, fmt.Sprintf(text.ErrorMissingOneOfRequiredOptions, []string{"json", "table", "yaml"})},
+		{"together", []string{"--json", "-p", "p", "--token", "t"}, fmt.Sprintf(text.ErrorOptionRequiresOption, "p", "user")},
+		{"required if", []string{"--json", "--user", "u", "-p", "p"}, fmt.Sprintf(text.ErrorOptionRequiresOption, "user", "token")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := define()
+			_, err := opt.Parse(tt.args)
+			if tt.err == "" && err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+		})
+	}
+
+	t.Run("required if order", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			opt := New()
+			opt.String("user", "")
+			opt.String("token", "", opt.RequiredIf("user"))
+			opt.String("otp", "", opt.RequiredIf("user"))
+			opt.String("key", "", opt.RequiredIf("user"))
+			_, err := opt.Parse([]string{"--user", "u"})
+			if err == nil || err.Error() != fmt.Sprintf(text.ErrorOptionRequiresOption, "user", "key") {
+				t.Fatalf("Error string didn't match expected value: %v", err)
+			}
+		}
+	})
+
+	t.Run("command", func(t *testing.T) {
+		opt := New()
+		opt.Bool("json", false)
+		opt.Bool("table", false)
+		opt.MutuallyExclusive("json", "table")
+		cmd := opt.NewCommand("show", "")
+		cmd.Bool("all", false)
+		cmd.Bool("none", false)
+		cmd.MutuallyExclusive("all", "none", "json")
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		_, err = cmd.Parse([]string{"--table", "--json"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorMutuallyExclusiveOptions, "json", "table") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		got := cmd.Help(HelpSynopsis)
+		expected := `SYNOPSIS:
+    go-getoptions.test show [--all | --none | --json] [--table] [<args>]
+
+`
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("undefined option in group did not panic")
+			}
+		}()
+		opt := New()
+		opt.Bool("json", false)
+		opt.MutuallyExclusive("json", "table")
+	})
+}
+
+// logLevel - custom Value type used for testing.
+type logLevel string
+
+func (l *logLevel) Set(s string) error {
+	switch s {
+	case "debug", "info", "warn", "error":
+		*l = logLevel(s)
+		return nil
+	}
+	return fmt.Errorf("unknown level")
+}
+
+func (l *logLevel) String() string { return string(*l) }
+
+func (l *logLevel) Type() string { return "level" }
+
+func TestVar(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		lvl := logLevel("info")
+		opt := New()
+		opt.Var(&lvl, "level", opt.Alias("l"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if lvl != "info" || opt.Called("level") {
+			t.Errorf("Unexpected value: %v, %v", lvl, opt.Called("level"))
+		}
+	})
+	t.Run("called", func(t *testing.T) {
+		lvl := logLevel("info")
+		opt := New()
+		opt.Var(&lvl, "level", opt.Alias("l"))
+		_, err := opt.Parse([]string{"-l", "debug"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if lvl != "debug" || opt.CalledAs("level") != "l" {
+			t.Errorf("Unexpected value: %v, %v", lvl, opt.CalledAs("level"))
+		}
+		if opt.Value("level").(*logLevel) != &lvl {
+			t.Errorf("Unexpected value: %v", opt.Value("level"))
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		lvl := logLevel("info")
+		opt := New()
+		opt.Var(&lvl, "level")
+		_, err := opt.Parse([]string{"--level=trace"})
+		expected := fmt.Sprintf(text.ErrorConvertToValue, "level", "level", "trace", "unknown level")
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		_, err = opt.Parse([]string{"--level"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorMissingArgument, "level") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+	t.Run("required", func(t *testing.T) {
+		lvl := logLevel("info")
+		opt := New()
+		opt.Var(&lvl, "level", opt.Required())
+		_, err := opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorMissingRequiredOption, "level") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+	t.Run("env", func(t *testing.T) {
+		os.Setenv("_get_opt_env_level", "warn")
+		defer os.Unsetenv("_get_opt_env_level")
+		lvl := logLevel("info")
+		opt := New()
+		opt.Var(&lvl, "level", opt.GetEnv("_get_opt_env_level"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if lvl != "warn" || opt.CalledAs("level") != "_get_opt_env_level" {
+			t.Errorf("Unexpected value: %v, %v", lvl, opt.CalledAs("level"))
+		}
+	})
+	t.Run("help", func(t *testing.T) {
+		lvl := logLevel("info")
+		opt := New()
+		opt.Var(&lvl, "level", opt.Description("log level"))
+		got := opt.Help(HelpSynopsis, HelpOptionList)
+		expected := `SYNOPSIS:
+    go-getoptions.test [--level <level>] [<args>]
+
+OPTIONS:
+    --level <level>    log level (default: info)
+
+`
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+		}
+	})
+}
+
 func TestLonesomeDash(t *testing.T) {
 	var stdin bool
 	opt := New()
@@ -1971,6 +2462,53 @@ func TestCompletion(t *testing.T) {
 	}
 }
 
+func TestShellCompletion(t *testing.T) {
+	called := false
+	exitFn = func(code int) { called = true }
+	defer func() { completionWriter = os.Stdout }()
+	opt := New()
+	opt.Bool("flag", false, opt.Alias("f"), opt.Description("Enable the flag"))
+	opt.String("format", "", opt.ValidValues("json", "yaml"))
+	deploy := opt.NewCommand("deploy", "Deploy\nthe app")
+	deploy.String("region", "", opt.Description("AWS region"))
+	deploy.Bool("force", false, opt.Description("Force the deploy"))
+	opt.NewCommand("show", "")
+
+	tests := []struct {
+		name     string
+		shell    string
+		compLine string
+		expected string
+	}{
+		{"zsh commands", "zsh", "test ", "deploy:Deploy the app\nshow\n"},
+		{"zsh options", "zsh", "test -", "-f:Enable the flag\n--flag:Enable the flag\n--format\n"},
+		{"zsh command options", "zsh", "test deploy --", "--flag:Enable the flag\n--force:Force the deploy\n--format\n--region:AWS region\n"},
+		{"zsh values", "zsh", "test --format=", "json\nyaml\n"},
+		{"fish commands", "fish", "test ", "deploy\tDeploy the app\nshow\n"},
+		{"fish command options", "fish", "test deploy --f", "--flag\tEnable the flag\n--force\tForce the deploy\n--format\n"},
+		{"fish values", "fish", "test --format=", "--format=json\n--format=yaml\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("COMP_LINE", tt.compLine)
+			t.Setenv(CompletionShellEnvVar, tt.shell)
+			called = false
+			buf := new(bytes.Buffer)
+			completionWriter = buf
+			_, err := opt.Parse([]string{})
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if !called {
+				t.Errorf("COMP_LINE set and exit wasn't called")
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Error\ngot: '%s', expected: '%s'\n", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
 // Verifies that a panic is reached when Command is called with a getoptions without a name.
 func TestCommandPanicWithNoNameInput(t *testing.T) {
 	defer func() {
@@ -2424,7 +2962,7 @@ Use 'go-getoptions.test help <command>' for extra details.
 SYNOPSIS:
     go-getoptions.test command [--help] [<args>]
 
-OPTIONS:
+GLOBAL OPTIONS:
     --help    (default: false)
 
 `
@@ -2474,7 +3012,7 @@ COMMANDS:
     help           Use 'go-getoptions.test command help <command>' for extra details.
     sub-command    
 
-OPTIONS:
+GLOBAL OPTIONS:
     --help    (default: false)
 
 `
@@ -2523,7 +3061,7 @@ OPTIONS:
 SYNOPSIS:
     go-getoptions.test command sub-command [--help] [<args>]
 
-OPTIONS:
+GLOBAL OPTIONS:
     --help    (default: false)
 
 `
@@ -2576,7 +3114,7 @@ SYNOPSIS:
 REQUIRED PARAMETERS:
     --required <string>
 
-OPTIONS:
+GLOBAL OPTIONS:
     --help                 (default: false)
 
 `
@@ -3140,9 +3678,8 @@ func TestGetEnv(t *testing.T) {
 		opt.IntVar(&v1, "opt1", 123, opt.GetEnv("_get_opt_env_test1"))
 		v2 := opt.Int("opt2", 123, opt.GetEnv("_get_opt_env_test2"))
 		_, err := opt.Parse([]string{})
-		// TODO: Handle errors when env vars don't have proper types
-		if err != nil {
-			t.Errorf("Unexpected error: %s", err)
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToInt, "_get_opt_env_test1", "abc") {
+			t.Errorf("Error string didn't match expected value: %v", err)
 		}
 		if v1 != 123 {
 			t.Errorf("Unexpected value: %d, %#v", v1, opt.Option("opt1"))
@@ -3196,6 +3733,249 @@ func TestGetEnv(t *testing.T) {
 		t.Log(buf.String())
 		cleanup()
 	})
+	/////////////////////////////////////////////////////////////////////////////
+	// Duration
+	/////////////////////////////////////////////////////////////////////////////
+	t.Run("duration env", func(t *testing.T) {
+		setup("2m")
+		buf := setupLogging()
+		var v1 time.Duration
+		opt := New()
+		opt.DurationVar(&v1, "opt1", time.Second, opt.GetEnv("_get_opt_env_test1"))
+		v2 := opt.Duration("opt2", time.Second, opt.GetEnv("_get_opt_env_test2"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if v1 != 2*time.Minute {
+			t.Errorf("Unexpected value: %v, %#v", v1, opt.Option("opt1"))
+		}
+		if *v2 != 2*time.Minute {
+			t.Errorf("Unexpected value: %v, %#v", *v2, opt.Option("opt2"))
+		}
+		t.Log(buf.String())
+		cleanup()
+	})
+	/////////////////////////////////////////////////////////////////////////////
+	// Time
+	/////////////////////////////////////////////////////////////////////////////
+	t.Run("time env", func(t *testing.T) {
+		setup("2021-02-03")
+		buf := setupLogging()
+		var v1 time.Time
+		opt := New()
+		opt.TimeVar(&v1, "opt1", time.Time{}, opt.TimeLayout("2006-01-02"), opt.GetEnv("_get_opt_env_test1"))
+		// The env var is read after all the modify functions are applied
+		v2 := opt.Time("opt2", time.Time{}, opt.GetEnv("_get_opt_env_test2"), opt.TimeLayout("2006-01-02"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC)
+		if !v1.Equal(expected) {
+			t.Errorf("Unexpected value: %v, %#v", v1, opt.Option("opt1"))
+		}
+		if !v2.Equal(expected) {
+			t.Errorf("Unexpected value: %v, %#v", *v2, opt.Option("opt2"))
+		}
+		t.Log(buf.String())
+		cleanup()
+	})
+	/////////////////////////////////////////////////////////////////////////////
+	// Slices, maps and increment
+	/////////////////////////////////////////////////////////////////////////////
+	t.Run("slice and map env", func(t *testing.T) {
+		os.Setenv("_get_opt_env_hosts", "a,b,c")
+		os.Setenv("_get_opt_env_ids", "1,3..4")
+		os.Setenv("_get_opt_env_waits", "1s,2m")
+		os.Setenv("_get_opt_env_labels", "k1=v1,k2=v2")
+		os.Setenv("_get_opt_env_uints", "5,6")
+		os.Setenv("_get_opt_env_verbose", "2")
+		defer func() {
+			for _, name := range []string{"hosts", "ids", "waits", "labels", "uints", "verbose"} {
+				os.Unsetenv("_get_opt_env_" + name)
+			}
+		}()
+		opt := New()
+		hosts := opt.StringSlice("host", 1, 1, opt.GetEnv("_get_opt_env_hosts"))
+		ids := opt.IntSlice("id", 1, 1, opt.GetEnv("_get_opt_env_ids"))
+		waits := opt.DurationSlice("wait", 1, 1, opt.GetEnv("_get_opt_env_waits"))
+		labels := opt.StringMap("label", 1, 1, opt.GetEnv("_get_opt_env_labels"))
+		uints := OptSlice[uint](opt, "uint", 1, 1, opt.GetEnv("_get_opt_env_uints"))
+		verbose := opt.Increment("verbose", 0, opt.GetEnv("_get_opt_env_verbose"))
+		_, err := opt.Parse([]string{"--verbose"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*hosts, []string{"a", "b", "c"}) || opt.CalledAs("host") != "_get_opt_env_hosts" {
+			t.Errorf("Unexpected value: %v, %s", *hosts, opt.CalledAs("host"))
+		}
+		if !reflect.DeepEqual(*ids, []int{1, 3, 4}) {
+			t.Errorf("Unexpected value: %v", *ids)
+		}
+		if !reflect.DeepEqual(*waits, []time.Duration{time.Second, 2 * time.Minute}) {
+			t.Errorf("Unexpected value: %v", *waits)
+		}
+		if !reflect.DeepEqual(labels, map[string]string{"k1": "v1", "k2": "v2"}) {
+			t.Errorf("Unexpected value: %v", labels)
+		}
+		if !reflect.DeepEqual(*uints, []uint{5, 6}) {
+			t.Errorf("Unexpected value: %v", *uints)
+		}
+		if *verbose != 3 {
+			t.Errorf("Unexpected value: %d", *verbose)
+		}
+	})
+	t.Run("slice env separator", func(t *testing.T) {
+		os.Setenv("_get_opt_env_hosts", "a b:c")
+		defer os.Unsetenv("_get_opt_env_hosts")
+		opt := New()
+		opt.SetEnvListSeparator(":")
+		hosts := opt.StringSlice("host", 1, 1, opt.GetEnv("_get_opt_env_hosts"))
+		cmd := opt.NewCommand("cmd", "")
+		cmdHosts := cmd.StringSlice("cmd-host", 1, 1, cmd.GetEnv("_get_opt_env_hosts"))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*hosts, []string{"a b", "c"}) || !reflect.DeepEqual(*cmdHosts, []string{"a b", "c"}) {
+			t.Errorf("Unexpected value: %v, %v", *hosts, *cmdHosts)
+		}
+	})
+	t.Run("slice env replaced by CLI", func(t *testing.T) {
+		os.Setenv("_get_opt_env_hosts", "a,b")
+		defer os.Unsetenv("_get_opt_env_hosts")
+		opt := New()
+		hosts := opt.StringSlice("host", 1, 1, opt.GetEnv("_get_opt_env_hosts"))
+		_, err := opt.Parse([]string{"--host", "c"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*hosts, []string{"c"}) {
+			t.Errorf("Unexpected value: %v", *hosts)
+		}
+	})
+	t.Run("valid values after env", func(t *testing.T) {
+		setup("c")
+		defer cleanup()
+		opt := New()
+		opt.String("opt1", "a", opt.GetEnv("_get_opt_env_test1"), opt.ValidValues("a", "b"))
+		_, err := opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorArgumentNotValidValue, "_get_opt_env_test1", "c", []string{"a", "b"}) {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+	t.Run("slice env error", func(t *testing.T) {
+		os.Setenv("_get_opt_env_ids", "1,x,3")
+		defer os.Unsetenv("_get_opt_env_ids")
+		opt := New()
+		ids := opt.IntSlice("id", 1, 99, opt.GetEnv("_get_opt_env_ids"))
+		_, err := opt.Parse([]string{})
+		var cErr *ConversionError
+		if !errors.As(err, &cErr) || cErr.Alias != "_get_opt_env_ids" || cErr.Arg != "x" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if opt.Called("id") || len(*ids) != 0 {
+			t.Errorf("Unexpected value: %v", *ids)
+		}
+
+		// The command line replaces the environment variable value
+		opt = New()
+		ids = opt.IntSlice("id", 1, 99, opt.GetEnv("_get_opt_env_ids"))
+		_, err = opt.Parse([]string{"--id", "4"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*ids, []int{4}) {
+			t.Errorf("Unexpected value: %v", *ids)
+		}
+	})
+	t.Run("separator panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SetEnvListSeparator did not panic")
+			}
+		}()
+		New().SetEnvListSeparator("")
+	})
+}
+
+func TestSetEnvPrefix(t *testing.T) {
+	env := map[string]string{
+		"MYAPP_PROFILE":       "dev",
+		"MYAPP_DRY_RUN":       "true",
+		"MYAPP_TAG":           "a,b",
+		"MYAPP_DEPLOY_REGION": "us-west-2",
+		"MYAPP_DEPLOY_SUB_ID": "3",
+		"AWS_REGION":          "eu-west-1",
+		"OTHER_NAME":          "other",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	}()
+
+	t.Run("values", func(t *testing.T) {
+		opt := New()
+		profile := opt.String("profile", "default")
+		opt.SetEnvPrefix("MYAPP")
+		dryRun := opt.Bool("dry-run", false)
+		tags := opt.StringSlice("tag", 1, 1)
+		deploy := opt.NewCommand("deploy", "")
+		region := deploy.String("region", "us-east-1")
+		id := deploy.NewCommand("sub", "").Int("id", 0)
+		other := opt.NewCommand("other", "")
+		other.SetEnvPrefix("OTHER")
+		name := other.String("name", "")
+		aws := deploy.String("aws-region", "", deploy.GetEnv("AWS_REGION"))
+		_, err := opt.Parse([]string{"--profile", "prod"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *profile != "prod" || !*dryRun || !reflect.DeepEqual(*tags, []string{"a", "b"}) {
+			t.Errorf("Unexpected values: %s, %v, %v", *profile, *dryRun, *tags)
+		}
+		if *region != "us-west-2" || deploy.CalledAs("region") != "MYAPP_DEPLOY_REGION" {
+			t.Errorf("Unexpected value: %s, %s", *region, deploy.CalledAs("region"))
+		}
+		if *id != 3 || *name != "other" || *aws != "eu-west-1" {
+			t.Errorf("Unexpected values: %d, %s, %s", *id, *name, *aws)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt := New()
+		opt.SetEnvPrefix("MYAPP")
+		opt.Bool("help", false, opt.Alias("?"))
+		opt.Bool("dry-run", false)
+		opt.String("token", "", opt.Hidden())
+		deploy := opt.NewCommand("deploy", "")
+		deploy.String("region", "us-east-1")
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		got := deploy.Help(HelpOptionList)
+		expected := `OPTIONS:
+    --region <string>    (default: "us-east-1", env: MYAPP_DEPLOY_REGION)
+
+GLOBAL OPTIONS:
+    --dry-run            (default: false, env: MYAPP_DRY_RUN)
+
+    --help|-?            (default: false)
+
+`
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+		}
+		if opt.Option("help").EnvVar != "" || opt.Option("token").EnvVar != "" {
+			t.Errorf("Unexpected env var: %s, %s", opt.Option("help").EnvVar, opt.Option("token").EnvVar)
+		}
+	})
 }
 
 func TestAll(t *testing.T) {
@@ -3336,3 +4116,396 @@ func TestInterruptContext(t *testing.T) {
 	}
 	t.Log(buf.String())
 }
+
+func TestSetCaseInsensitive(t *testing.T) {
+	setup := func() *GetOpt {
+		opt := New()
+		opt.SetCaseInsensitive()
+		opt.String("profile", "default", opt.Alias("p"))
+		opt.Bool("color", true, opt.Negatable())
+		opt.Bool("verbose", false, opt.Alias("v"))
+		opt.Bool("version", false, opt.Alias("V"))
+		opt.Bool("dry-run", false, opt.Alias("n"), opt.Negatable())
+		return opt
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		option   string
+		calledAs string
+		value    interface{}
+	}{
+		{"full match", []string{"--PROFILE", "dev"}, "profile", "PROFILE", "dev"},
+		{"lazy match", []string{"--PRO", "dev"}, "profile", "PRO", "dev"},
+		{"lazy match verbose", []string{"--VERB"}, "verbose", "VERB", true},
+		{"lazy match negated", []string{"--NO-COL"}, "color", "NO-COL", false},
+		{"lazy match not negated", []string{"--COL"}, "color", "COL", true},
+		{"exact match first", []string{"-V"}, "version", "V", true},
+		{"exact match first", []string{"-v"}, "verbose", "v", true},
+		{"negated", []string{"--NO-Color"}, "color", "NO-Color", false},
+		{"not negated", []string{"--COLOR"}, "color", "COLOR", true},
+		{"short alias prefix of negated", []string{"-n"}, "dry-run", "n", true},
+		{"short alias prefix of negated upper", []string{"-N"}, "dry-run", "N", true},
+		{"lazy match negated short", []string{"--NO-D"}, "dry-run", "NO-D", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := setup()
+			_, err := opt.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if opt.CalledAs(tt.option) != tt.calledAs || opt.Value(tt.option) != tt.value {
+				t.Errorf("Unexpected value: %s, %v", opt.CalledAs(tt.option), opt.Value(tt.option))
+			}
+		})
+	}
+
+	t.Run("ambiguous", func(t *testing.T) {
+		opt := New()
+		opt.SetCaseInsensitive()
+		opt.Bool("a", false)
+		opt.Bool("b", false, opt.Alias("A"))
+		_, err := opt.Parse([]string{"--bb"})
+		if err == nil || err.Error() != fmt.Sprintf(text.MessageOnUnknown, "bb") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		opt = New()
+		opt.SetCaseInsensitive()
+		opt.Bool("ab", false)
+		opt.Bool("AB", false)
+		_, err = opt.Parse([]string{"--Ab"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorAmbiguousArgument, "Ab", []string{"AB", "ab"}) {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+
+	t.Run("case sensitive", func(t *testing.T) {
+		opt := New()
+		opt.String("profile", "default")
+		_, err := opt.Parse([]string{"--PROFILE", "dev"})
+		if err == nil || err.Error() != fmt.Sprintf(text.MessageOnUnknown, "PROFILE") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+
+	t.Run("commands", func(t *testing.T) {
+		called := false
+		opt := New()
+		opt.SetCaseInsensitive()
+		opt.SetUnknownMode(Pass)
+		opt.Bool("help", false)
+		cmd := opt.NewCommand("deploy", "")
+		region := cmd.String("region", "")
+		cmd.SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error {
+			called = true
+			return nil
+		})
+		opt.HelpCommand("")
+		remaining, err := opt.Parse([]string{"DEPLOY", "--REGION", "us-west-2"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.Dispatch(context.Background(), "help", remaining)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !called || *region != "us-west-2" || cmd.CalledAs("region") != "REGION" {
+			t.Errorf("Unexpected values: %v, %s, %s", called, *region, cmd.CalledAs("region"))
+		}
+
+		exitFn = func(code int) {}
+		buf := new(bytes.Buffer)
+		opt.Writer = buf
+		err = opt.Dispatch(context.Background(), "help", []string{"help", "Deploy"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if buf.String() != cmd.Help() {
+			t.Errorf("Unexpected help: %s", buf.String())
+		}
+	})
+
+	t.Run("ambiguous commands", func(t *testing.T) {
+		fn := func(context.Context, *GetOpt, []string) error { return nil }
+		opt := New()
+		opt.SetCaseInsensitive()
+		opt.NewCommand("deploy", "").SetCommandFn(fn)
+		opt.NewCommand("Deploy", "").SetCommandFn(fn)
+		opt.HelpCommand("")
+		expected := fmt.Sprintf(text.ErrorAmbiguousCommand, "DEPLOY", []string{"Deploy", "deploy"})
+		err := opt.Dispatch(context.Background(), "help", []string{"DEPLOY"})
+		var e *AmbiguousCommandError
+		if !errors.As(err, &e) || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		err = opt.Dispatch(context.Background(), "help", []string{"help", "DEPLOY"})
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		// An exact match takes precedence
+		err = opt.Dispatch(context.Background(), "help", []string{"Deploy"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		exitFn = func(code int) {}
+		buf := new(bytes.Buffer)
+		completionWriter = buf
+		defer func() { completionWriter = os.Stdout; os.Setenv("COMP_LINE", "") }()
+		os.Setenv("COMP_LINE", "test DEP --R")
+		opt := New()
+		opt.SetCaseInsensitive()
+		opt.NewCommand("deploy", "").String("region", "")
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if buf.String() != "deploy\n" {
+			t.Errorf("Unexpected completion: %q", buf.String())
+		}
+		buf.Reset()
+		os.Setenv("COMP_LINE", "test DEPLOY --R")
+		_, err = opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if buf.String() != "--region\n" {
+			t.Errorf("Unexpected completion: %q", buf.String())
+		}
+	})
+}
+
+func TestSetPrefixMatching(t *testing.T) {
+	setup := func(mode PrefixMatching) *GetOpt {
+		opt := New()
+		opt.SetPrefixMatching(mode)
+		opt.String("profile", "")
+		opt.Bool("verbose", false)
+		opt.Bool("force", false, opt.NoAbbrev())
+		opt.Bool("quiet", false)
+		opt.Bool("quota", false)
+		opt.NewCommand("deploy", "").Bool("version", false)
+		return opt
+	}
+	tests := []struct {
+		name   string
+		mode   PrefixMatching
+		args   []string
+		option string
+		err    string
+	}{
+		{"unique only", PrefixMatchingUniqueOnly, []string{"--pro", "dev"}, "profile", ""},
+		{"unique only full", PrefixMatchingUniqueOnly, []string{"--verbose"}, "verbose", ""},
+		{"unique only ambiguous", PrefixMatchingUniqueOnly, []string{"--qu"}, "", fmt.Sprintf(text.ErrorAmbiguousArgument, "qu", []string{"quiet", "quota"})},
+		{"on", PrefixMatchingOn, []string{"--pro", "dev"}, "profile", ""},
+		{"on ambiguous", PrefixMatchingOn, []string{"--qu"}, "", fmt.Sprintf(text.ErrorAmbiguousArgument, "qu", []string{"quiet", "quota"})},
+		// The modes only differ when the abbreviation also matches options of a child command
+		{"unique only command option", PrefixMatchingUniqueOnly, []string{"--ver"}, "", fmt.Sprintf(text.ErrorAmbiguousArgument, "ver", []string{"verbose", "version"})},
+		{"on command option", PrefixMatchingOn, []string{"--ver"}, "verbose", ""},
+		{"off", PrefixMatchingOff, []string{"--pro", "dev"}, "", fmt.Sprintf(text.MessageOnUnknown, "pro")},
+		{"off full", PrefixMatchingOff, []string{"--profile", "dev"}, "profile", ""},
+		{"no abbrev", PrefixMatchingOn, []string{"--for"}, "", fmt.Sprintf(text.MessageOnUnknown, "for")},
+		{"no abbrev full", PrefixMatchingOn, []string{"--force"}, "force", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := setup(tt.mode)
+			_, err := opt.Parse(tt.args)
+			if tt.err == "" && err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+			if tt.option != "" && !opt.Called(tt.option) {
+				t.Errorf("Option %s not called", tt.option)
+			}
+		})
+	}
+
+	t.Run("inherited", func(t *testing.T) {
+		opt := New()
+		opt.SetPrefixMatching(PrefixMatchingOff)
+		cmd := opt.NewCommand("deploy", "")
+		cmd.String("region", "")
+		_, err := cmd.Parse([]string{"--reg", "x"})
+		if err == nil || err.Error() != fmt.Sprintf(text.MessageOnUnknown, "reg") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		cmd.NewCommand("sub", "").SetPrefixMatching(PrefixMatchingOn).String("name", "")
+		_, err = cmd.commands["sub"].Parse([]string{"--na", "x"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("command option no abbrev", func(t *testing.T) {
+		opt := New()
+		opt.Bool("verbose", false)
+		opt.NewCommand("deploy", "").Bool("version", false, opt.NoAbbrev())
+		_, err := opt.Parse([]string{"--ver"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !opt.Called("verbose") {
+			t.Errorf("Option verbose not called")
+		}
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		opt := New()
+		opt.SetAbbreviationWarnings()
+		opt.String("profile", "")
+		cmd := opt.NewCommand("deploy", "")
+		cmd.String("region", "")
+		buf := new(bytes.Buffer)
+		opt.Writer = buf
+		cmd.Writer = buf
+		_, err := opt.Parse([]string{"--pro", "dev", "--profile", "dev"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		_, err = cmd.Parse([]string{"--reg", "x"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := "WARNING: " + fmt.Sprintf(text.MessageOnAbbreviation, "pro", "profile") + "\n" +
+			"WARNING: " + fmt.Sprintf(text.MessageOnAbbreviation, "reg", "region") + "\n"
+		if buf.String() != expected {
+			t.Errorf("Unexpected warnings: %q", buf.String())
+		}
+	})
+}
+
+func TestHidden(t *testing.T) {
+	setup := func() *GetOpt {
+		opt := New()
+		opt.String("profile", "", opt.ValidValues("dev", "prod"))
+		opt.Bool("debug", false, opt.Hidden())
+		opt.String("trace", "", opt.Hidden(), opt.ValidValues("on", "off"))
+		opt.NewCommand("deploy", "deploy the app").SetCommandFn(func(context.Context, *GetOpt, []string) error { return nil })
+		opt.NewCommand("internal", "internal tooling").SetHidden().SetCommandFn(func(context.Context, *GetOpt, []string) error { return nil })
+		opt.HelpCommand("")
+		return opt
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		opt := setup()
+		_, err := opt.Parse([]string{"--debug", "--trace", "on"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !opt.Called("debug") || opt.Value("trace") != "on" {
+			t.Errorf("Hidden options not called")
+		}
+		err = opt.Dispatch(context.Background(), "help", []string{"internal"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt := setup()
+		h := opt.Help()
+		for _, s := range []string{"--debug", "--trace", "internal"} {
+			if strings.Contains(h, s) {
+				t.Errorf("Help shows hidden %s:\n%s", s, h)
+			}
+		}
+		for _, s := range []string{"--profile", "deploy"} {
+			if !strings.Contains(h, s) {
+				t.Errorf("Help doesn't show %s:\n%s", s, h)
+			}
+		}
+		t.Setenv(ShowHiddenEnvVar, "1")
+		h = opt.Help()
+		for _, s := range []string{"--debug", "--trace", "internal", "--profile", "deploy"} {
+			if !strings.Contains(h, s) {
+				t.Errorf("Help doesn't show %s:\n%s", s, h)
+			}
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		opt := setup()
+		if opt.completion.GetChildByName("internal").Name != "" || opt.completion.GetChildByName("deploy").Name != "deploy" {
+			t.Errorf("Unexpected command nodes: %v", opt.completion.Children)
+		}
+		help := opt.completion.GetChildByName("help").GetChildByName("custom").Entries
+		sort.Strings(help)
+		if !reflect.DeepEqual(help, []string{"deploy", "help"}) {
+			t.Errorf("Unexpected help completions: %v", help)
+		}
+		options := opt.completion.GetChildByName("options").Entries
+		withArg := opt.completion.GetChildByName("options-with-arg")
+		for _, e := range append(options, withArg.Entries...) {
+			if strings.Contains(e, "debug") || strings.Contains(e, "trace") {
+				t.Errorf("Unexpected option completion: %s", e)
+			}
+		}
+		if withArg.GetChildByName("--trace").Name != "" || withArg.GetChildByName("--profile").Name != "--profile" {
+			t.Errorf("Unexpected valid values completions: %v", withArg.Children)
+		}
+	})
+
+	t.Run("root", func(t *testing.T) {
+		opt := New().SetHidden()
+		if len(opt.completion.Children) != 2 {
+			t.Errorf("Unexpected root nodes: %v", opt.completion.Children)
+		}
+	})
+
+	t.Run("suggestions", func(t *testing.T) {
+		opt := setup()
+		_, err := opt.Parse([]string{"--debog"})
+		if err == nil || err.Error() != fmt.Sprintf(text.MessageOnUnknown, "debog") {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		err = opt.Dispatch(context.Background(), "help", []string{"internl"})
+		if err == nil || err.Error() != "not a command: 'internl'" {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+	})
+}
+
+func TestGroup(t *testing.T) {
+	opt := New()
+	opt.SetGroupOrder("Networking")
+	opt.Bool("debug", false)
+	deploy := opt.NewCommand("deploy", "")
+	deploy.String("region", "", opt.Group("Networking"))
+	deploy.Int("port", 0, opt.Group("Networking"))
+	deploy.Bool("color", false, opt.Group("Output"))
+	deploy.String("profile", "")
+	_, err := opt.Parse([]string{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	got := deploy.Help(HelpOptionList)
+	expected := `OPTIONS:
+    --profile <string>    (default: "")
+
+Networking:
+    --port <int>          (default: 0)
+
+    --region <string>     (default: "")
+
+Output:
+    --color               (default: false)
+
+GLOBAL OPTIONS:
+    --debug               (default: false)
+
+`
+	if got != expected {
+		t.Errorf("Unexpected help:\n%s\n%s", got, firstDiff(got, expected))
+	}
+	deploy.SetGroupOrder("Output", "Networking")
+	got = deploy.Help(HelpOptionList)
+	if strings.Index(got, "Output:") > strings.Index(got, "Networking:") {
+		t.Errorf("Unexpected group order:\n%s", got)
+	}
+}
@@ -0,0 +1,198 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package help
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// DocFormat - Format of the generated reference documentation.
+type DocFormat int
+
+// Documentation formats
+const (
+	MarkdownFormat DocFormat = iota
+	AsciiDocFormat
+)
+
+// DocPageName - Returns the documentation file name for the full command name.
+// For example: `prog deploy` -> `prog-deploy.md`
+func DocPageName(name string, format DocFormat) string {
+	if format == AsciiDocFormat {
+		return ManPageName(name) + ".adoc"
+	}
+	return ManPageName(name) + ".md"
+}
+
+// docRow - Returns the option table cells: option, aliases, type, default, env var, required and description.
+// cell formats each value, code values are the option names, the default and the env var.
+func docRow(opt *option.Option, cell func(s string, code bool) string) []string {
+	aliases := []string{}
+	for _, alias := range opt.Aliases {
+		if alias != opt.Name {
+			aliases = append(aliases, cell(optionName(alias), true))
+		}
+	}
+	def := opt.DefaultStr
+	if opt.IsRequired {
+		def = ""
+	}
+	required := ""
+	if opt.IsRequired {
+		required = text.DocRequiredValue
+	}
+	annotations := []string{}
+	if len(opt.ValidValues) > 0 {
+		label := "valid values"
+		if opt.OptType == option.StringMapType {
+			label = "valid keys"
+		}
+		annotations = append(annotations, fmt.Sprintf("%s: %s", label, strings.Join(opt.ValidValues, ", ")))
+	}
+	if opt.IsDeprecated {
+		annotations = append(annotations, deprecated(optionName(opt.ReplacedBy)))
+	}
+	description := strings.Join(strings.Fields(opt.Description), " ")
+	if len(annotations) > 0 {
+		if description != "" {
+			description += " "
+		}
+		description += fmt.Sprintf("(%s)", strings.Join(annotations, "; "))
+	}
+	return []string{
+		cell(optionName(opt.Name), true), strings.Join(aliases, ", "), cell(opt.TypeName(), false),
+		cell(def, true), cell(opt.EnvVar, true), cell(required, false), cell(description, false),
+	}
+}
+
+// docHeader - Returns the option table header.
+func docHeader() []string {
+	return []string{
+		text.DocOptionColumn, text.DocAliasesColumn, text.DocTypeColumn, text.DocDefaultColumn,
+		text.DocEnvColumn, text.DocRequiredColumn, text.DocDescriptionColumn,
+	}
+}
+
+// markdownCell - Returns the cell escaped for a Markdown table, code cells are wrapped in backticks.
+func markdownCell(s string, code bool) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	if code {
+		return "`" + s + "`"
+	}
+	return s
+}
+
+// asciiDocCell - Returns the cell escaped for an AsciiDoc table, code cells are literal monospace.
+func asciiDocCell(s string, code bool) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	if code {
+		return "`+" + s + "+`"
+	}
+	return s
+}
+
+// Doc - Returns the Markdown or AsciiDoc reference page of the command.
+// The page includes the synopsis, description, command list linking to the command pages,
+// option tables and a link to the parent command page.
+func Doc(p Page, format DocFormat) string {
+	h1, h2, h3 := "# ", "## ", "### "
+	codeStart, codeEnd := "```\n", "```\n"
+	if format == AsciiDocFormat {
+		h1, h2, h3 = "= ", "== ", "=== "
+		codeStart, codeEnd = "----\n", "----\n"
+	}
+	link := func(name string) string {
+		label := name[strings.LastIndex(name, " ")+1:]
+		if format == AsciiDocFormat {
+			return fmt.Sprintf("xref:%s[%s]", DocPageName(name, format), label)
+		}
+		return fmt.Sprintf("[%s](%s)", label, DocPageName(name, format))
+	}
+
+	out := h1 + p.Name + "\n"
+
+	if p.Description != "" {
+		out += "\n" + strings.TrimRight(p.Description, "\n") + "\n"
+	}
+
+	if lines := p.synopsisLines(); len(lines) > 0 {
+		out += "\n" + h2 + text.HelpSynopsisHeader + "\n\n"
+		out += codeStart + strings.Join(lines, "\n") + "\n" + codeEnd
+	}
+
+	if len(p.Commands) > 0 {
+		out += "\n" + h2 + text.HelpCommandsHeader + "\n\n"
+		for _, name := range p.children() {
+			description := strings.Join(strings.Fields(p.Commands[name[len(p.Name)+1:]]), " ")
+			if format == AsciiDocFormat {
+				if description == "" {
+					description = "{empty}"
+				}
+				out += fmt.Sprintf("%s:: %s\n", link(name), description)
+				continue
+			}
+			out += "* " + link(name)
+			if description != "" {
+				out += " - " + description
+			}
+			out += "\n"
+		}
+	}
+
+	if len(p.Options) > 0 || len(p.Global) > 0 {
+		out += "\n" + h2 + text.HelpOptionsHeader + "\n"
+		titles, sections := p.sections()
+		for i, section := range sections {
+			if len(section) == 0 {
+				continue
+			}
+			if titles[i] != "" {
+				out += "\n" + h3 + titles[i] + "\n"
+			}
+			out += "\n"
+			if format == AsciiDocFormat {
+				out += "[options=\"header\"]\n|===\n"
+				out += "|" + strings.Join(docHeader(), " |") + "\n"
+				for _, opt := range section {
+					out += "\n"
+					for _, cell := range docRow(opt, asciiDocCell) {
+						out += "|" + cell + "\n"
+					}
+				}
+				out += "|===\n"
+				continue
+			}
+			out += "| " + strings.Join(docHeader(), " | ") + " |\n"
+			out += strings.Repeat("| --- ", len(docHeader())) + "|\n"
+			for _, opt := range section {
+				out += "| " + strings.Join(docRow(opt, markdownCell), " | ") + " |\n"
+			}
+		}
+	}
+
+	if parent := p.parent(); parent != "" {
+		out += "\n" + h2 + text.HelpSeeAlsoHeader + "\n\n"
+		if format == AsciiDocFormat {
+			out += link(parent) + "\n"
+		} else {
+			out += "* " + link(parent) + "\n"
+		}
+	}
+	return out
+}
@@ -0,0 +1,219 @@
+package help
+
+import (
+	"testing"
+
+	"github.com/DavidGamba/go-getoptions/option"
+)
+
+func TestDoc(t *testing.T) {
+	debug := func() *option.Option {
+		b := false
+		o := option.New("debug", option.BoolType, &b).SetDefaultStr("false").SetEnvVar("PROG_DEBUG")
+		o.SetAlias("d")
+		return o
+	}
+	region := func() *option.Option {
+		s := "us-east-1"
+		o := option.New("region", option.StringType, &s).SetDefaultStr(`"us-east-1"`).SetDescription("AWS\nregion | zone")
+		o.Group = "Networking"
+		o.ValidValues = []string{"us-east-1", "eu-west-1"}
+		return o
+	}
+	labels := func() *option.Option {
+		m := map[string]string{}
+		o := option.New("label", option.StringMapType, &m).SetDefaultStr("{}")
+		o.ValidValues = []string{"env"}
+		o.SetDeprecated("", "")
+		return o
+	}
+	name := func() *option.Option {
+		s := ""
+		return option.New("name", option.StringType, &s).SetRequired("")
+	}
+	program := Page{
+		Name:        "prog",
+		Description: "Program that does things.\n\nSecond paragraph.\n",
+		Synopsis:    "SYNOPSIS:\n    prog [--debug|-d] <command> [<args>]\n",
+		Options:     []*option.Option{debug()},
+		Commands:    map[string]string{"deploy": "deploy\nthe app", "show": ""},
+	}
+	command := Page{
+		Name:     "prog deploy",
+		Synopsis: "SYNOPSIS:\n    prog deploy --name <string> [--label <key=value>]\n                [--region <string>] [<args>]\n",
+		Options:  []*option.Option{region(), name(), labels()},
+		Global:   []*option.Option{debug()},
+	}
+
+	tests := []struct {
+		name     string
+		page     Page
+		format   DocFormat
+		file     string
+		expected string
+	}{
+		{"markdown program", program, MarkdownFormat, "prog.md", "# prog\n" + `
+Program that does things.
+
+Second paragraph.
+
+## SYNOPSIS
+
+` + "```" + `
+prog [--debug|-d] <command> [<args>]
+` + "```" + `
+
+## COMMANDS
+
+* [deploy](prog-deploy.md) - deploy the app
+* [show](prog-show.md)
+
+## OPTIONS
+
+| Option | Aliases | Type | Default | Env | Required | Description |
+| --- | --- | --- | --- | --- | --- | --- |
+| ` + "`--debug` | `-d` | bool | `false` | `PROG_DEBUG`" + ` |  |  |
+`},
+		{"markdown command", command, MarkdownFormat, "prog-deploy.md", "# prog deploy\n" + `
+## SYNOPSIS
+
+` + "```" + `
+prog deploy --name <string> [--label <key=value>]
+            [--region <string>] [<args>]
+` + "```" + `
+
+## OPTIONS
+
+| Option | Aliases | Type | Default | Env | Required | Description |
+| --- | --- | --- | --- | --- | --- | --- |
+| ` + "`--name`" + ` |  | string |  |  | yes |  |
+| ` + "`--label`" + ` |  | map[string]string | ` + "`{}`" + ` |  |  | (valid keys: env; deprecated) |
+
+### Networking
+
+| Option | Aliases | Type | Default | Env | Required | Description |
+| --- | --- | --- | --- | --- | --- | --- |
+| ` + "`--region`" + ` |  | string | ` + "`\"us-east-1\"`" + ` |  |  | AWS region \| zone (valid values: us-east-1, eu-west-1) |
+
+### GLOBAL OPTIONS
+
+| Option | Aliases | Type | Default | Env | Required | Description |
+| --- | --- | --- | --- | --- | --- | --- |
+| ` + "`--debug` | `-d` | bool | `false` | `PROG_DEBUG`" + ` |  |  |
+
+## SEE ALSO
+
+* [prog](prog.md)
+`},
+		{"asciidoc program", program, AsciiDocFormat, "prog.adoc", `= prog
+
+Program that does things.
+
+Second paragraph.
+
+== SYNOPSIS
+
+----
+prog [--debug|-d] <command> [<args>]
+----
+
+== COMMANDS
+
+xref:prog-deploy.adoc[deploy]:: deploy the app
+xref:prog-show.adoc[show]:: {empty}
+
+== OPTIONS
+
+[options="header"]
+|===
+|Option |Aliases |Type |Default |Env |Required |Description
+
+|` + "`+--debug+`" + `
+|` + "`+-d+`" + `
+|bool
+|` + "`+false+`" + `
+|` + "`+PROG_DEBUG+`" + `
+|
+|
+|===
+`},
+		{"asciidoc command", command, AsciiDocFormat, "prog-deploy.adoc", `= prog deploy
+
+== SYNOPSIS
+
+----
+prog deploy --name <string> [--label <key=value>]
+            [--region <string>] [<args>]
+----
+
+== OPTIONS
+
+[options="header"]
+|===
+|Option |Aliases |Type |Default |Env |Required |Description
+
+|` + "`+--name+`" + `
+|
+|string
+|
+|
+|yes
+|
+
+|` + "`+--label+`" + `
+|
+|map[string]string
+|` + "`+{}+`" + `
+|
+|
+|(valid keys: env; deprecated)
+|===
+
+=== Networking
+
+[options="header"]
+|===
+|Option |Aliases |Type |Default |Env |Required |Description
+
+|` + "`+--region+`" + `
+|
+|string
+|` + "`+\"us-east-1\"+`" + `
+|
+|
+|AWS region \| zone (valid values: us-east-1, eu-west-1)
+|===
+
+=== GLOBAL OPTIONS
+
+[options="header"]
+|===
+|Option |Aliases |Type |Default |Env |Required |Description
+
+|` + "`+--debug+`" + `
+|` + "`+-d+`" + `
+|bool
+|` + "`+false+`" + `
+|` + "`+PROG_DEBUG+`" + `
+|
+|
+|===
+
+== SEE ALSO
+
+xref:prog.adoc[prog]
+`},
+		{"empty", Page{Name: "prog"}, MarkdownFormat, "prog.md", "# prog\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if file := DocPageName(tt.page.Name, tt.format); file != tt.file {
+				t.Errorf("Unexpected file name: %s", file)
+			}
+			got := Doc(tt.page, tt.format)
+			if got != tt.expected {
+				t.Errorf("Unexpected doc:\n%s\n%s", got, firstDiff(got, tt.expected))
+			}
+		})
+	}
+}
@@ -53,8 +53,26 @@ func Name(scriptName, name, description string) string {
 	return fmt.Sprintf("%s:\n%s\n", text.HelpNameHeader, indent(out))
 }
 
+// GroupKind - Relationship between the options in a Group.
+type GroupKind int
+
+// Group kinds in the order they are rendered
+const (
+	OneRequired       GroupKind = iota // Rendered as: (--a | --b)
+	MutuallyExclusive                  // Rendered as: [--a | --b]
+	RequiredTogether                   // Rendered as: [--a --b]
+)
+
+// Group - Options that are rendered together in the synopsis.
+type Group struct {
+	Kind  GroupKind
+	Names []string // Option names
+}
+
 // Synopsis - Return a default synopsis.
-func Synopsis(scriptName, name, args string, options []*option.Option, commands []string) string {
+// Options that are part of a group are rendered together.
+// When an option is part of multiple groups it is only rendered in the first one.
+func Synopsis(scriptName, name, args string, options []*option.Option, commands []string, groups ...Group) string {
 	synopsisName := scriptName
 	if scriptName != "" {
 		synopsisName += fmt.Sprintf(" %s", name)
@@ -65,9 +83,42 @@ func Synopsis(scriptName, name, args string, options []*option.Option, commands
 	if name != "" {
 		scriptName += " " + name
 	}
+	groups = append([]Group{}, groups...)
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Kind < groups[j].Kind })
+	grouped := map[string]bool{}
+	groupSynopsis := []string{}
+	for _, g := range groups {
+		gOptions := []*option.Option{}
+		for _, n := range g.Names {
+			for _, opt := range options {
+				if opt.Name == n && !grouped[n] {
+					gOptions = append(gOptions, opt)
+					grouped[n] = true
+				}
+			}
+		}
+		if len(gOptions) == 0 {
+			continue
+		}
+		txt := []string{}
+		for _, opt := range gOptions {
+			txt = append(txt, opt.HelpSynopsis)
+		}
+		switch g.Kind {
+		case OneRequired:
+			groupSynopsis = append(groupSynopsis, "("+strings.Join(txt, " | ")+")")
+		case MutuallyExclusive:
+			groupSynopsis = append(groupSynopsis, "["+strings.Join(txt, " | ")+"]")
+		default:
+			groupSynopsis = append(groupSynopsis, "["+strings.Join(txt, " ")+"]")
+		}
+	}
 	normalOptions := []*option.Option{}
 	requiredOptions := []*option.Option{}
 	for _, option := range options {
+		if grouped[option.Name] {
+			continue
+		}
 		if option.IsRequired {
 			requiredOptions = append(requiredOptions, option)
 		} else {
@@ -79,21 +130,34 @@ func Synopsis(scriptName, name, args string, options []*option.Option, commands
 	optSynopsis := func(opt *option.Option) string {
 		txt := ""
 		wrap := wrapFn(!opt.IsRequired, "[", "]")
+		repeat := false
 		switch opt.OptType {
-		case option.BoolType, option.StringType, option.IntType, option.Float64Type:
-			txt += wrap(opt.HelpSynopsis)
-		case option.StringRepeatType, option.IntRepeatType, option.StringMapType:
-			if opt.IsRequired {
-				wrap = wrapFn(opt.IsRequired, "<", ">")
-			}
-			txt += wrap(opt.HelpSynopsis) + "..."
+		case option.StringRepeatType, option.IntRepeatType, option.DurationRepeatType, option.StringMapType:
+			repeat = true
+		case option.ValueType:
+			// Custom types that accept multiple arguments have MaxArgs set.
+			repeat = opt.MaxArgs > 0
+		}
+		if !repeat {
+			return wrap(opt.HelpSynopsis)
 		}
+		if opt.IsRequired {
+			wrap = wrapFn(opt.IsRequired, "<", ">")
+		}
+		txt += wrap(opt.HelpSynopsis) + "..."
 		return txt
 	}
+	synopsisList := []string{}
+	for _, option := range requiredOptions {
+		synopsisList = append(synopsisList, optSynopsis(option))
+	}
+	synopsisList = append(synopsisList, groupSynopsis...)
+	for _, option := range normalOptions {
+		synopsisList = append(synopsisList, optSynopsis(option))
+	}
 	var out string
 	line := synopsisName
-	for _, option := range append(requiredOptions, normalOptions...) {
-		syn := optSynopsis(option)
+	for _, syn := range synopsisList {
 		// fmt.Printf("%d - %d - %d | %s | %s\n", len(line), len(syn), len(line)+len(syn), syn, line)
 		if len(line)+len(syn) > 80 {
 			out += line + "\n"
@@ -121,6 +185,69 @@ func Synopsis(scriptName, name, args string, options []*option.Option, commands
 	return fmt.Sprintf("%s:\n%s\n", text.HelpSynopsisHeader, out)
 }
 
+// argSynopsis - Required arguments are rendered as <name>, optional ones as
+// [<name>] and the variadic tail with a trailing "...".
+func argSynopsis(arg *option.Option) string {
+	txt := "<" + arg.HelpArgName + ">"
+	switch arg.OptType {
+	case option.StringRepeatType, option.IntRepeatType, option.DurationRepeatType:
+		txt += "..."
+	}
+	return wrapFn(!arg.IsRequired, "[", "]")(txt)
+}
+
+// ArgSynopsis - Return the synopsis of the given positional arguments.
+// For example: `<src> [<dst>...]`
+func ArgSynopsis(args []*option.Option) string {
+	txt := []string{}
+	for _, arg := range args {
+		txt = append(txt, argSynopsis(arg))
+	}
+	return strings.Join(txt, " ")
+}
+
+// ArgumentList - Return a formatted list of positional arguments and their descriptions.
+// Arguments are listed in the order they are given.
+func ArgumentList(args []*option.Option) string {
+	if len(args) == 0 {
+		return ""
+	}
+	names := []string{}
+	for _, arg := range args {
+		names = append(names, argSynopsis(arg))
+	}
+	factor := longestStringLen(names) + 4
+	padding := strings.Repeat(" ", factor)
+	out := ""
+	for i, arg := range args {
+		txt := ""
+		if arg.Description != "" {
+			txt += strings.ReplaceAll(arg.Description, "\n", "\n    "+padding)
+		}
+		if len(arg.ValidValues) > 0 {
+			if txt != "" {
+				txt += " "
+			}
+			txt += fmt.Sprintf("(valid values: %s)", strings.Join(arg.ValidValues, "|"))
+		}
+		out += indent(pad(txt != "", names[i], factor)) + txt + "\n\n"
+	}
+	return fmt.Sprintf("%s:\n%s", text.HelpArgumentsHeader, out)
+}
+
+func deprecated(replacement string) string {
+	if replacement == "" {
+		return "deprecated"
+	}
+	return fmt.Sprintf("deprecated, use %s", replacement)
+}
+
+// DeprecatedAnnotation - Returns the annotation added to the description of deprecated commands.
+// For example: `(deprecated, use deploy)`
+func DeprecatedAnnotation(replacement string) string {
+	return "(" + deprecated(replacement) + ")"
+}
+
 // CommandList -
 // commandMap => name: description
 func CommandList(commandMap map[string]string) string {
@@ -163,64 +290,135 @@ func pad(do bool, s string, factor int) string {
 }
 
 // OptionList - Return a formatted list of options and their descriptions.
+// Options with a group are listed in a section per group, see OptionSections.
 func OptionList(options []*option.Option) string {
+	return OptionSections(options, nil, nil)
+}
+
+// OptionSections - Return a formatted list of options and their descriptions split in sections.
+//
+// Required options without a group are listed first, followed by the rest of the options without a group.
+// Then there is a section per group, titled with the group name, in the given group order followed by the remaining groups sorted by name.
+// The global options, the options inherited from the parent command, are listed last.
+func OptionSections(options, global []*option.Option, groupOrder []string) string {
 	synopsisLength := 0
+	for _, opt := range append(append([]*option.Option{}, options...), global...) {
+		if l := len(opt.HelpSynopsis); l > synopsisLength {
+			synopsisLength = l
+		}
+	}
 	normalOptions := []*option.Option{}
 	requiredOptions := []*option.Option{}
+	groups := map[string][]*option.Option{}
 	for _, opt := range options {
-		l := len(opt.HelpSynopsis)
-		if l > synopsisLength {
-			synopsisLength = l
-		}
-		if opt.IsRequired {
+		if opt.Group != "" {
+			groups[opt.Group] = append(groups[opt.Group], opt)
+		} else if opt.IsRequired {
 			requiredOptions = append(requiredOptions, opt)
 		} else {
 			normalOptions = append(normalOptions, opt)
 		}
 	}
-	option.Sort(normalOptions)
-	option.Sort(requiredOptions)
-	helpString := func(opt *option.Option) string {
-		txt := ""
-		factor := synopsisLength + 4
-		padding := strings.Repeat(" ", factor)
-		txt += indent(pad(!opt.IsRequired || opt.Description != "" || opt.EnvVar != "", opt.HelpSynopsis, factor))
-		if opt.Description != "" {
-			description := strings.ReplaceAll(opt.Description, "\n", "\n    "+padding)
-			txt += description
+	out := ""
+	section := func(header string, list []*option.Option) {
+		if len(list) == 0 {
+			return
 		}
-		if !opt.IsRequired {
-			if opt.Description != "" {
-				txt += " "
-			}
-			txt += fmt.Sprintf("(default: %s", opt.DefaultStr)
-			if opt.EnvVar != "" {
-				txt += fmt.Sprintf(", env: %s", opt.EnvVar)
-			}
-			txt += ")\n\n"
-		} else {
-			if opt.EnvVar != "" {
-				if opt.Description != "" {
-					txt += " "
-				}
-				txt += fmt.Sprintf("(env: %s)", opt.EnvVar)
-			}
-			txt += "\n\n"
+		sortRequiredFirst(list)
+		out += fmt.Sprintf("%s:\n", header)
+		for _, opt := range list {
+			out += optionHelp(opt, synopsisLength)
 		}
-		return txt
 	}
-	out := ""
-	if len(requiredOptions) > 0 {
-		out += fmt.Sprintf("%s:\n", text.HelpRequiredOptionsHeader)
-		for _, option := range requiredOptions {
-			out += helpString(option)
+	section(text.HelpRequiredOptionsHeader, requiredOptions)
+	section(text.HelpOptionsHeader, normalOptions)
+	for _, name := range groupNames(groups, groupOrder) {
+		section(name, groups[name])
+	}
+	section(text.HelpGlobalOptionsHeader, append([]*option.Option{}, global...))
+	return out
+}
+
+// sortRequiredFirst - Sorts the options by name with the required options first.
+func sortRequiredFirst(list []*option.Option) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].IsRequired != list[j].IsRequired {
+			return list[i].IsRequired
+		}
+		return list[i].Name < list[j].Name
+	})
+}
+
+// groupNames - Returns the group names in the given order followed by the remaining ones sorted by name.
+func groupNames(groups map[string][]*option.Option, order []string) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, name := range order {
+		if _, ok := groups[name]; ok && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
 		}
 	}
-	if len(normalOptions) > 0 {
-		out += fmt.Sprintf("%s:\n", text.HelpOptionsHeader)
-		for _, option := range normalOptions {
-			out += helpString(option)
+	rest := []string{}
+	for name := range groups {
+		if !seen[name] {
+			rest = append(rest, name)
 		}
 	}
-	return out
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// optionHelp - Returns the synopsis of the option padded to synopsisLength followed by its description and annotations.
+func optionHelp(opt *option.Option, synopsisLength int) string {
+	txt := ""
+	factor := synopsisLength + 4
+	padding := strings.Repeat(" ", factor)
+	annotations := optionAnnotations(opt)
+	txt += indent(pad(opt.Description != "" || len(annotations) > 0, opt.HelpSynopsis, factor))
+	if opt.Description != "" {
+		description := strings.ReplaceAll(opt.Description, "\n", "\n    "+padding)
+		txt += description
+	}
+	if len(annotations) > 0 {
+		if opt.Description != "" {
+			txt += " "
+		}
+		txt += fmt.Sprintf("(%s)", strings.Join(annotations, ", "))
+	}
+	txt += "\n\n"
+	return txt
+}
+
+// optionAnnotations - Returns the default, valid values, env var and deprecation details of the option.
+func optionAnnotations(opt *option.Option) []string {
+	annotations := []string{}
+	if !opt.IsRequired {
+		annotations = append(annotations, fmt.Sprintf("default: %s", opt.DefaultStr))
+	}
+	if len(opt.ValidValues) > 0 {
+		label := "valid values"
+		if opt.OptType == option.StringMapType {
+			label = "valid keys"
+		}
+		annotations = append(annotations, fmt.Sprintf("%s: %s", label, strings.Join(opt.ValidValues, "|")))
+	}
+	if opt.EnvVar != "" {
+		annotations = append(annotations, fmt.Sprintf("env: %s", opt.EnvVar))
+	}
+	if opt.IsDeprecated {
+		annotations = append(annotations, deprecated(optionName(opt.ReplacedBy)))
+	}
+	return annotations
+}
+
+// optionName - Returns the name with one dash for single letter names and two dashes otherwise.
+func optionName(name string) string {
+	switch len(name) {
+	case 0:
+		return ""
+	case 1:
+		return "-" + name
+	}
+	return "--" + name
 }
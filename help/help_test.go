@@ -127,6 +127,24 @@ func TestHelp(t *testing.T) {
     help.test log --bool|-b --float <float64> <--ii <int>>... --int <int>
                   <-m <key=value>>... <--ss <string>>... <-z <key=value>>...
                   <command> [<args>]
+`},
+		{"Synopsis groups", Synopsis(scriptName, "log", "",
+			[]*option.Option{
+				boolOpt(),
+				intOpt().SetRequired(""),
+				floatOpt(),
+				ssOpt(),
+				iiOpt(),
+				mOpt(),
+			}, []string{},
+			Group{Kind: RequiredTogether, Names: []string{"ss", "ii"}},
+			Group{Kind: MutuallyExclusive, Names: []string{"bool", "float", "missing"}},
+			Group{Kind: OneRequired, Names: []string{"float", "m"}},
+			Group{Kind: MutuallyExclusive, Names: []string{"missing"}},
+		),
+			`SYNOPSIS:
+    help.test log --int <int> (--float <float64> | -m <key=value>) [--bool|-b]
+                  [--ss <string> --ii <int>] [<args>]
 `},
 		{"OptionList nil", OptionList(nil), ""},
 		{"OptionList empty", OptionList([]*option.Option{}), ""},
@@ -246,6 +264,81 @@ OPTIONS:
 
     --string-repeat <my_value>    string repeat (default: [], env: STRING_REPEAT)
 
+`},
+		{"OptionList valid values", OptionList([]*option.Option{
+			func() *option.Option {
+				s := ""
+				return option.New("format", option.StringType, &s)
+			}().SetDefaultStr(`"json"`).SetDescription("output format").SetValidValues("json", "yaml").SetEnvVar("FORMAT"),
+			ssOpt().SetDefaultStr("[]").SetValidValues("a", "b").SetRequired(""),
+			mOpt().SetDefaultStr("{}").SetValidValues("k1", "k2"),
+		}), `REQUIRED PARAMETERS:
+    --ss <string>        (valid values: a|b)
+
+OPTIONS:
+    --format <string>    output format (default: "json", valid values: json|yaml, env: FORMAT)
+
+    -m <key=value>       (default: {}, valid keys: k1|k2)
+
+`},
+		{"OptionList deprecated", OptionList([]*option.Option{
+			boolOpt().SetDefaultStr("false").SetDeprecated("", ""),
+			intOpt().SetDefaultStr("0").SetDeprecated("", "i"),
+			floatOpt().SetDefaultStr("0.0").SetDeprecated("", "ratio"),
+		}), `OPTIONS:
+    --bool|-b            (default: false, deprecated)
+
+    --float <float64>    (default: 0.0, deprecated, use --ratio)
+
+    --int <int>          (default: 0, deprecated, use -i)
+
+`},
+		{"OptionSections", OptionSections([]*option.Option{
+			func() *option.Option { o := boolOpt().SetDefaultStr("false"); o.Group = "Output"; return o }(),
+			func() *option.Option { o := intOpt().SetDefaultStr("0"); o.Group = "Networking"; return o }(),
+			func() *option.Option { o := floatOpt().SetRequired(""); o.Group = "Networking"; return o }(),
+			func() *option.Option { o := ssOpt().SetDefaultStr("[]"); o.Group = "Auth"; return o }(),
+			iiOpt().SetDefaultStr("[]"),
+		}, []*option.Option{
+			mOpt().SetDefaultStr("{}"),
+		}, []string{"Networking", "Missing", "Networking"}), `OPTIONS:
+    --ii <int>           (default: [])
+
+Networking:
+    --float <float64>
+
+    --int <int>          (default: 0)
+
+Auth:
+    --ss <string>        (default: [])
+
+Output:
+    --bool|-b            (default: false)
+
+GLOBAL OPTIONS:
+    -m <key=value>       (default: {})
+
+`},
+		{"DeprecatedAnnotation", DeprecatedAnnotation("") + " " + DeprecatedAnnotation("deploy"), "(deprecated) (deprecated, use deploy)"},
+		{"ArgSynopsis", ArgSynopsis(nil), ""},
+		{"ArgSynopsis", ArgSynopsis([]*option.Option{
+			func() *option.Option { o := intOpt(); o.IsRequired = true; o.HelpArgName = "count"; return o }(),
+			func() *option.Option { o := iiOpt(); o.HelpArgName = "ii"; return o }(),
+		}), "<count> [<ii>...]"},
+		{"ArgumentList", ArgumentList(nil), ""},
+		{"ArgumentList", ArgumentList([]*option.Option{
+			func() *option.Option {
+				o := ssOpt().SetValidValues("a", "b")
+				o.IsRequired, o.HelpArgName, o.Description = true, "ss", "multiline\ndescription"
+				return o
+			}(),
+			func() *option.Option { o := floatOpt(); o.HelpArgName = "float"; return o }(),
+		}), `ARGUMENTS:
+    <ss>...      multiline
+                 description (valid values: a|b)
+
+    [<float>]
+
 `},
 		{"CommandList", CommandList(nil), ""},
 		{"CommandList", CommandList(map[string]string{}), ""},
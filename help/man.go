@@ -0,0 +1,221 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package help
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// Page - Command details used to generate documentation.
+type Page struct {
+	Name        string            // Full command name, for example: "prog deploy"
+	Description string            // Command description
+	Synopsis    string            // Synopsis as returned by the Synopsis function
+	Options     []*option.Option  // Options defined in the command
+	Global      []*option.Option  // Options inherited from the parent command
+	GroupOrder  []string          // Order of the option groups
+	Commands    map[string]string // Child command names and descriptions
+}
+
+// parent - Returns the full name of the parent command, empty for the program.
+func (p Page) parent() string {
+	if i := strings.LastIndex(p.Name, " "); i >= 0 {
+		return p.Name[:i]
+	}
+	return ""
+}
+
+// children - Returns the full names of the child commands sorted by name.
+func (p Page) children() []string {
+	names := []string{}
+	for name := range p.Commands {
+		names = append(names, p.Name+" "+name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// synopsisLines - Returns the synopsis lines without the header and the indentation.
+func (p Page) synopsisLines() []string {
+	lines := []string{}
+	if p.Synopsis == "" {
+		return lines
+	}
+	for i, line := range strings.Split(strings.TrimRight(p.Synopsis, "\n"), "\n") {
+		if i == 0 && line == text.HelpSynopsisHeader+":" {
+			continue
+		}
+		lines = append(lines, strings.TrimPrefix(line, strings.Repeat(" ", Indentation)))
+	}
+	return lines
+}
+
+// sections - Returns the option sections: the options without a group, one per group and the global options.
+// The first section has an empty title.
+func (p Page) sections() ([]string, [][]*option.Option) {
+	ungrouped := []*option.Option{}
+	groups := map[string][]*option.Option{}
+	for _, opt := range p.Options {
+		if opt.Group != "" {
+			groups[opt.Group] = append(groups[opt.Group], opt)
+		} else {
+			ungrouped = append(ungrouped, opt)
+		}
+	}
+	titles := []string{""}
+	sections := [][]*option.Option{ungrouped}
+	for _, name := range groupNames(groups, p.GroupOrder) {
+		titles = append(titles, name)
+		sections = append(sections, groups[name])
+	}
+	titles = append(titles, text.HelpGlobalOptionsHeader)
+	sections = append(sections, append([]*option.Option{}, p.Global...))
+	for _, s := range sections {
+		sortRequiredFirst(s)
+	}
+	return titles, sections
+}
+
+// envOptions - Returns the options with an environment variable sorted by variable name.
+func (p Page) envOptions() []*option.Option {
+	list := []*option.Option{}
+	for _, opt := range append(append([]*option.Option{}, p.Options...), p.Global...) {
+		if opt.EnvVar != "" {
+			list = append(list, opt)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].EnvVar < list[j].EnvVar })
+	return list
+}
+
+// optionText - Returns the option description followed by its annotations.
+func optionText(opt *option.Option) string {
+	txt := opt.Description
+	if annotations := optionAnnotations(opt); len(annotations) > 0 {
+		if txt != "" {
+			txt += " "
+		}
+		txt += fmt.Sprintf("(%s)", strings.Join(annotations, ", "))
+	}
+	return txt
+}
+
+// ManPageName - Returns the man page name for the full command name.
+// For example: `prog deploy` -> `prog-deploy`
+func ManPageName(name string) string {
+	return strings.ReplaceAll(name, " ", "-")
+}
+
+// roffEscape - Escapes backslashes and dashes and protects lines that start with a control character.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// roffParagraphs - Escapes the text and separates the paragraphs with .PP requests.
+func roffParagraphs(s string) string {
+	lines := strings.Split(roffEscape(s), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ".PP"
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// ManPage - Returns the roff man page, in section 1, of the command.
+func ManPage(p Page) string {
+	out := ""
+	program := strings.SplitN(p.Name, " ", 2)[0]
+	out += fmt.Sprintf(".TH \"%s\" \"1\" \"\" \"%s\"\n", roffEscape(strings.ToUpper(ManPageName(p.Name))), roffEscape(program))
+
+	out += fmt.Sprintf(".SH %s\n", text.HelpNameHeader)
+	out += roffEscape(ManPageName(p.Name))
+	if p.Description != "" {
+		out += ` \- ` + roffEscape(strings.SplitN(p.Description, "\n", 2)[0])
+	}
+	out += "\n"
+
+	out += fmt.Sprintf(".SH %s\n.nf\n", text.HelpSynopsisHeader)
+	for _, line := range p.synopsisLines() {
+		out += roffEscape(line) + "\n"
+	}
+	out += ".fi\n"
+
+	if p.Description != "" {
+		out += fmt.Sprintf(".SH %s\n", text.HelpDescriptionHeader)
+		out += roffParagraphs(p.Description)
+	}
+
+	if len(p.Commands) > 0 {
+		out += fmt.Sprintf(".SH %s\n", text.HelpCommandsHeader)
+		for _, name := range p.children() {
+			command := name[len(p.Name)+1:]
+			out += fmt.Sprintf(".TP\n\\fB%s\\fR\n", roffEscape(command))
+			if p.Commands[command] != "" {
+				out += roffParagraphs(p.Commands[command])
+			}
+		}
+	}
+
+	if len(p.Options) > 0 || len(p.Global) > 0 {
+		out += fmt.Sprintf(".SH %s\n", text.HelpOptionsHeader)
+		titles, sections := p.sections()
+		for i, section := range sections {
+			if len(section) == 0 {
+				continue
+			}
+			if titles[i] != "" {
+				out += fmt.Sprintf(".SS %s\n", roffEscape(titles[i]))
+			}
+			for _, opt := range section {
+				out += fmt.Sprintf(".TP\n\\fB%s\\fR\n", roffEscape(opt.HelpSynopsis))
+				if txt := optionText(opt); txt != "" {
+					out += roffParagraphs(txt)
+				}
+			}
+		}
+	}
+
+	if env := p.envOptions(); len(env) > 0 {
+		out += fmt.Sprintf(".SH %s\n", text.HelpEnvironmentHeader)
+		for _, opt := range env {
+			out += fmt.Sprintf(".TP\n\\fB%s\\fR\n", roffEscape(opt.EnvVar))
+			out += roffEscape(fmt.Sprintf("Sets the value of %s.", optionName(opt.Name))) + "\n"
+		}
+	}
+
+	related := p.children()
+	if parent := p.parent(); parent != "" {
+		related = append([]string{parent}, related...)
+	}
+	if len(related) > 0 {
+		out += fmt.Sprintf(".SH %s\n", text.HelpSeeAlsoHeader)
+		for i, name := range related {
+			out += fmt.Sprintf(".BR %s (1)", roffEscape(ManPageName(name)))
+			if i < len(related)-1 {
+				out += ","
+			}
+			out += "\n"
+		}
+	}
+	return out
+}
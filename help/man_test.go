@@ -0,0 +1,115 @@
+package help
+
+import (
+	"testing"
+
+	"github.com/DavidGamba/go-getoptions/option"
+)
+
+func TestManPage(t *testing.T) {
+	debug := func() *option.Option {
+		b := false
+		o := option.New("debug", option.BoolType, &b).SetDefaultStr("false").SetEnvVar("PROG_DEBUG")
+		return o
+	}
+	region := func() *option.Option {
+		s := "us-east-1"
+		o := option.New("region", option.StringType, &s).SetDefaultStr(`"us-east-1"`).SetDescription("AWS region")
+		o.Group = "Networking"
+		return o
+	}
+	name := func() *option.Option {
+		s := ""
+		return option.New("name", option.StringType, &s).SetRequired("")
+	}
+
+	tests := []struct {
+		name     string
+		page     Page
+		expected string
+	}{
+		{"program", Page{
+			Name:        "prog",
+			Description: "Program that does\nthings.\n\n.starts with a dot \\ backslash",
+			Synopsis:    "SYNOPSIS:\n    prog [--debug] <command> [<args>]\n",
+			Options:     []*option.Option{debug()},
+			Commands:    map[string]string{"deploy": "deploy the app", "show": ""},
+		}, `.TH "PROG" "1" "" "prog"
+.SH NAME
+prog \- Program that does
+.SH SYNOPSIS
+.nf
+prog [\-\-debug] <command> [<args>]
+.fi
+.SH DESCRIPTION
+Program that does
+things.
+.PP
+\&.starts with a dot \e backslash
+.SH COMMANDS
+.TP
+\fBdeploy\fR
+deploy the app
+.TP
+\fBshow\fR
+.SH OPTIONS
+.TP
+\fB\-\-debug\fR
+(default: false, env: PROG_DEBUG)
+.SH ENVIRONMENT
+.TP
+\fBPROG_DEBUG\fR
+Sets the value of \-\-debug.
+.SH SEE ALSO
+.BR prog\-deploy (1),
+.BR prog\-show (1)
+`},
+		{"command", Page{
+			Name:       "prog deploy",
+			Synopsis:   "SYNOPSIS:\n    prog deploy --name <string> [--debug]\n                [--region <string>] [<args>]\n",
+			Options:    []*option.Option{region(), name()},
+			Global:     []*option.Option{debug()},
+			GroupOrder: []string{"Networking"},
+		}, `.TH "PROG\-DEPLOY" "1" "" "prog"
+.SH NAME
+prog\-deploy
+.SH SYNOPSIS
+.nf
+prog deploy \-\-name <string> [\-\-debug]
+            [\-\-region <string>] [<args>]
+.fi
+.SH OPTIONS
+.TP
+\fB\-\-name <string>\fR
+.SS Networking
+.TP
+\fB\-\-region <string>\fR
+AWS region (default: "us\-east\-1")
+.SS GLOBAL OPTIONS
+.TP
+\fB\-\-debug\fR
+(default: false, env: PROG_DEBUG)
+.SH ENVIRONMENT
+.TP
+\fBPROG_DEBUG\fR
+Sets the value of \-\-debug.
+.SH SEE ALSO
+.BR prog (1)
+`},
+		{"empty", Page{Name: "prog"}, `.TH "PROG" "1" "" "prog"
+.SH NAME
+prog
+.SH SYNOPSIS
+.nf
+.fi
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ManPage(tt.page)
+			if got != tt.expected {
+				t.Errorf("Unexpected man page:\n%s\n%s", got, firstDiff(got, tt.expected))
+			}
+		})
+	}
+}
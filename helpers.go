@@ -53,3 +53,11 @@ func isOption(s string, mode Mode) (options []string, argument string) {
 	}
 	return []string{}, ""
 }
+
+// optionDash - Returns the dash prefix of the given option argument.
+func optionDash(s string) string {
+	if strings.HasPrefix(s, "--") {
+		return "--"
+	}
+	return "-"
+}
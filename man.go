@@ -0,0 +1,113 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/DavidGamba/go-getoptions/help"
+)
+
+// helpPage - Returns the details of the command used to generate documentation.
+// The help command is not included in the command list.
+func (gopt *GetOpt) helpPage() help.Page {
+	scriptName := ""
+	if gopt.isCommand {
+		scriptName = getCommandName(gopt.parent)
+	}
+	options, global := gopt.helpOptionsByOrigin()
+	commands := gopt.helpCommandMap()
+	for name, command := range gopt.commands {
+		if command.isHelpCommand {
+			delete(commands, name)
+		}
+	}
+	return help.Page{
+		Name:        getCommandName(gopt),
+		Description: gopt.description,
+		Synopsis:    gopt.helpSynopsis(scriptName),
+		Options:     options,
+		Global:      global,
+		GroupOrder:  gopt.helpGroupOrder(),
+		Commands:    commands,
+	}
+}
+
+// docCommands - Returns the command and its children that are documented.
+// Hidden commands, unless shown with ShowHiddenEnvVar, and the help command are skipped.
+func (gopt *GetOpt) docCommands() []*GetOpt {
+	list := []*GetOpt{gopt}
+	for _, command := range gopt.helpCommands() {
+		if command.isHelpCommand {
+			continue
+		}
+		list = append(list, command.docCommands()...)
+	}
+	return list
+}
+
+// ManPages - Returns the roff man pages of the command and its children indexed by file name.
+// For example: `prog.1` and `prog-deploy.1`.
+//
+// The pages include the NAME, SYNOPSIS, DESCRIPTION, COMMANDS, OPTIONS, ENVIRONMENT and SEE ALSO sections.
+func (gopt *GetOpt) ManPages() map[string]string {
+	gopt.passOptionsToChildren()
+	pages := map[string]string{}
+	for _, command := range gopt.docCommands() {
+		page := command.helpPage()
+		pages[help.ManPageName(page.Name)+".1"] = help.ManPage(page)
+	}
+	return pages
+}
+
+// WriteManPages - Writes the roff man pages of the command and its children to dir.
+// See ManPages.
+func (gopt *GetOpt) WriteManPages(dir string) error {
+	return writePages(dir, gopt.ManPages())
+}
+
+// writePages - Writes the pages, indexed by file name, to dir.
+func writePages(dir string, pages map[string]string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	for name, page := range pages {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(page), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ManCommand - Adds a hidden command that writes the man pages of the program to the directory given as argument.
+// The directory defaults to the current directory.
+//
+//     opt.ManCommand("man")
+//
+// Called as `prog man ./share/man/man1`.
+func (gopt *GetOpt) ManCommand(name string) *GetOpt {
+	cmd := gopt.NewCommand(name, "Write the man pages of the program to the given directory.").SetHidden()
+	cmd.HelpSynopsisArgs("[<dir>]")
+	cmd.SetCommandFn(func(ctx context.Context, c *GetOpt, args []string) error {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		root := gopt
+		for root.parent != nil {
+			root = root.parent
+		}
+		return root.WriteManPages(dir)
+	})
+	return cmd
+}
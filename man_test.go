@@ -0,0 +1,141 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestManPages(t *testing.T) {
+	fn := func(context.Context, *GetOpt, []string) error { return nil }
+	setup := func() *GetOpt {
+		opt := New()
+		opt.Self("prog", "Program that does things.")
+		opt.Bool("debug", false, opt.GetEnv("PROG_DEBUG"))
+		deploy := opt.NewCommand("deploy", "deploy the app")
+		deploy.String("region", "", opt.Description("AWS region"))
+		deploy.SetCommandFn(fn)
+		deploy.NewCommand("rollback", "rollback the deploy").SetCommandFn(fn)
+		opt.NewCommand("secret", "").SetHidden().SetCommandFn(fn)
+		opt.ManCommand("man")
+		opt.HelpCommand("")
+		return opt
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		opt := setup()
+		pages := opt.ManPages()
+		names := []string{}
+		for name := range pages {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if strings.Join(names, " ") != "prog-deploy-rollback.1 prog-deploy.1 prog.1" {
+			t.Errorf("Unexpected pages: %v", names)
+		}
+		page := pages["prog.1"]
+		if !strings.Contains(page, `prog \- Program that does things.`) || !strings.Contains(page, ".BR prog\\-deploy (1)\n") {
+			t.Errorf("Unexpected page:\n%s", page)
+		}
+		if strings.Contains(page, "secret") || strings.Contains(page, "help") || strings.Contains(page, "\\fBman") {
+			t.Errorf("Unexpected command in page:\n%s", page)
+		}
+		page = pages["prog-deploy.1"]
+		if !strings.Contains(page, ".SS GLOBAL OPTIONS\n.TP\n\\fB\\-\\-debug\\fR\n") || !strings.Contains(page, "AWS region") ||
+			!strings.Contains(page, ".BR prog (1),\n.BR prog\\-deploy\\-rollback (1)\n") {
+			t.Errorf("Unexpected page:\n%s", page)
+		}
+	})
+
+	t.Run("show hidden", func(t *testing.T) {
+		t.Setenv(ShowHiddenEnvVar, "true")
+		opt := setup()
+		pages := opt.ManPages()
+		if _, ok := pages["prog-secret.1"]; !ok {
+			t.Errorf("Missing hidden command page")
+		}
+		if _, ok := pages["prog-man.1"]; !ok {
+			t.Errorf("Missing hidden command page")
+		}
+	})
+
+	t.Run("write", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "man1")
+		opt := setup()
+		err := opt.WriteManPages(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, "prog-deploy.1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if string(b) != opt.ManPages()["prog-deploy.1"] {
+			t.Errorf("Unexpected page:\n%s", string(b))
+		}
+	})
+
+	t.Run("write errors", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		err := os.WriteFile(file, []byte{}, 0644)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		opt := setup()
+		err = opt.WriteManPages(filepath.Join(file, "man1"))
+		if err == nil {
+			t.Errorf("Expected error")
+		}
+		dir := t.TempDir()
+		err = os.Mkdir(filepath.Join(dir, "prog.1"), 0755)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.WriteManPages(dir)
+		if err == nil {
+			t.Errorf("Expected error")
+		}
+	})
+
+	t.Run("command", func(t *testing.T) {
+		dir := t.TempDir()
+		opt := setup()
+		err := opt.Dispatch(context.Background(), "help", []string{"man", dir})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "prog-deploy-rollback.1")); err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		dir = t.TempDir()
+		err = os.Chdir(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer os.Chdir(wd)
+		opt = setup()
+		err = opt.Dispatch(context.Background(), "help", []string{"man"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "prog.1")); err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+}
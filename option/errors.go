@@ -0,0 +1,63 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package option
+
+import (
+	"fmt"
+
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// ConversionError - Error returned when an argument can't be converted to the option type.
+type ConversionError struct {
+	Name   string // Option name.
+	Alias  string // Alias used to call the option.
+	Arg    string // Argument that failed to convert.
+	Type   string // Type the argument was converted to: int, float64, duration, time or the custom Value type.
+	Layout string // Expected layout for time options.
+	Err    error  // Error returned by the Set method of custom Value types.
+}
+
+func (e *ConversionError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf(text.ErrorConvertToValue, e.Alias, e.Type, e.Arg, e.Err)
+	}
+	switch e.Type {
+	case "float64":
+		return fmt.Sprintf(text.ErrorConvertToFloat64, e.Alias, e.Arg)
+	case "duration":
+		return fmt.Sprintf(text.ErrorConvertToDuration, e.Alias, e.Arg)
+	case "time":
+		return fmt.Sprintf(text.ErrorConvertToTime, e.Alias, e.Arg, e.Layout)
+	default: // int
+		return fmt.Sprintf(text.ErrorConvertToInt, e.Alias, e.Arg)
+	}
+}
+
+// Unwrap - Returns the error returned by the Set method of custom Value types.
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// RequiredOptionError - Error returned when a required option wasn't called.
+type RequiredOptionError struct {
+	Name string // Option name.
+	Msg  string // Custom error message given to SetRequired.
+}
+
+func (e *RequiredOptionError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return fmt.Sprintf(text.ErrorMissingRequiredOption, e.Name)
+}
+
+func (opt *Option) conversionError(arg, t string) error {
+	return &ConversionError{Name: opt.Name, Alias: opt.UsedAlias, Arg: arg, Type: t}
+}
@@ -16,6 +16,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/DavidGamba/go-getoptions/text"
 )
@@ -39,24 +40,165 @@ const (
 	StringRepeatType
 	IntRepeatType
 	StringMapType
+	ValueType
+	DurationType
+	DurationRepeatType
+	TimeType
 )
 
+func (t Type) String() string {
+	switch t {
+	case BoolType:
+		return "bool"
+	case StringType:
+		return "string"
+	case IntType:
+		return "int"
+	case Float64Type:
+		return "float64"
+	case StringRepeatType:
+		return "[]string"
+	case IntRepeatType:
+		return "[]int"
+	case StringMapType:
+		return "map[string]string"
+	case ValueType:
+		return "value"
+	case DurationType:
+		return "duration"
+	case DurationRepeatType:
+		return "[]duration"
+	case TimeType:
+		return "time"
+	}
+	return "unknown"
+}
+
+// Value - Interface implemented by custom option types.
+//
+// Set parses the command line argument and saves it, String returns the
+// string representation of the current value, used as the default in the
+// help, and Type returns the type name used as the help argument name.
+type Value interface {
+	Set(string) error
+	String() string
+	Type() string
+}
+
+// Getter - Optional interface for Value types.
+// When implemented, Option.Value returns the result of Get instead of the Value itself.
+type Getter interface {
+	Value
+	Get() interface{}
+}
+
+// Cloner - Optional interface for Value types.
+// When implemented, values are saved and validated on a copy before updating the option,
+// and values from the command line replace the values set from other sources.
+//
+// Clone returns a copy with its own data, Copy sets the data to the data of
+// the given Value and Clear removes the values of types that accumulate them.
+type Cloner interface {
+	Value
+	Clone() Value
+	Copy(from Value) error
+	Clear()
+}
+
+// Completion - Completion source used by positional arguments.
+type Completion int
+
+// Completion sources
+const (
+	NoCompletion   Completion = iota
+	FileCompletion            // Complete file and directory names
+	DirCompletion             // Complete directory names
+	ListCompletion            // Complete the entries in CompletionList
+)
+
+func (c Completion) String() string {
+	switch c {
+	case FileCompletion:
+		return "file"
+	case DirCompletion:
+		return "dir"
+	case ListCompletion:
+		return "list"
+	}
+	return "none"
+}
+
+// Source - Origin of the option value.
+type Source int
+
+// Value sources
+const (
+	DefaultSource      Source = iota // Option default
+	CLISource                        // Command line, UsedAlias is the alias used
+	EnvSource                        // Environment variable, UsedAlias is the variable name
+	ConfigSource                     // Configuration file, UsedAlias is the file and key
+	ProgrammaticSource               // Set in code, UsedAlias is the option name
+)
+
+func (s Source) String() string {
+	switch s {
+	case CLISource:
+		return "cli"
+	case EnvSource:
+		return "env"
+	case ConfigSource:
+		return "config"
+	case ProgrammaticSource:
+		return "programmatic"
+	}
+	return "default"
+}
+
+// Validator - Function that checks the option value once it has been saved.
+// Errors should use opt.UsedAlias to name the option.
+type Validator func(opt *Option) error
+
 // Option - main object
 type Option struct {
 	Name           string
 	Aliases        []string
-	EnvVar         string  // Env Var that sets the option value
-	Called         bool    // Indicates if the option was passed on the command line
-	UsedAlias      string  // Alias/Env var used when the option was called
-	Handler        Handler // method used to handle the option
-	IsOptional     bool    // Indicates if an option has an optional argument
-	MapKeysToLower bool    // Indicates if the option of map type has it keys set ToLower
-	OptType        Type    // Option Type
-	MinArgs        int     // minimum args when using multi
-	MaxArgs        int     // maximum args when using multi
-
-	IsRequired    bool   // Indicates if the option is required
-	IsRequiredErr string // Error message for the required option
+	EnvVar         string   // Env Var that sets the option value
+	EnvErr         error    // Error saving the Env Var value, returned when parsing
+	Called         bool     // Indicates if the option was passed on the command line
+	UsedAlias      string   // Alias/Env var used when the option was called
+	Source         Source   // Origin of the option value
+	IsSecret       bool     // Indicates if the option value is redacted when dumped
+	NoAbbrev       bool     // Indicates if the option only matches its full aliases
+	IsHidden       bool     // Indicates if the option is excluded from the help and completions
+	IsDeprecated   bool     // Indicates if the option is deprecated
+	DeprecatedMsg  string   // Deprecation message shown when the option is used
+	ReplacedBy     string   // Name of the option that replaces the deprecated option
+	Warned         bool     // Indicates if the deprecation warning was already shown during the current Parse
+	Group          string   // Help section the option is listed in
+	Handler        Handler  // method used to handle the option
+	IsOptional     bool     // Indicates if an option has an optional argument
+	MapKeysToLower bool     // Indicates if the option of map type has it keys set ToLower
+	OptType        Type     // Option Type
+	MinArgs        int      // minimum args when using multi
+	MaxArgs        int      // maximum args when using multi
+	TimeLayout     string   // Layout used to parse time options
+	ValidValues    []string // Allowed arguments, for map types the allowed keys
+
+	// Negatable bool options
+	NegationPrefixes []string // Prefixes used to build the NegatedAliases, for example: no-
+	NegatedAliases   []string // Aliases that set a bool option to false, for example: no-color
+
+	IsRequired    bool     // Indicates if the option is required
+	IsRequiredErr string   // Error message for the required option
+	RequiredIf    []string // Names of the options that make this option required when called
+
+	// Positional arguments
+	Completion     Completion // Completion source
+	CompletionList []string   // Completion entries for ListCompletion
+
+	Validators []Validator // Checks run after the option value is saved
+	MinLength  int         // Minimum amount of values for slice and map types, 0 for no limit
+	MaxLength  int         // Maximum amount of values for slice and map types, 0 for no limit
 
 	// Help
 	DefaultStr   string // String representation of default value
@@ -64,7 +206,8 @@ type Option struct {
 	HelpArgName  string // Optional arg name used for help
 	HelpSynopsis string // Help synopsis
 
-	boolDefault bool // copy of bool default value
+	boolDefault    bool   // copy of bool default value
+	negationPrefix string // help representation of the negation prefixes, for example: [no-]
 
 	// Pointer receivers:
 	pBool    *bool              // receiver for bool pointer
@@ -74,6 +217,10 @@ type Option struct {
 	pStringS *[]string          // receiver for string slice pointer
 	pIntS    *[]int             // receiver for int slice pointer
 	pStringM *map[string]string // receiver for string map pointer
+	pValue   Value              // receiver for custom Value types
+	pDur     *time.Duration     // receiver for duration pointer
+	pDurS    *[]time.Duration   // receiver for duration slice pointer
+	pTime    *time.Time         // receiver for time pointer
 
 	Unknown bool // Temporary marker used during parsing
 }
@@ -105,6 +252,20 @@ func New(name string, optType Type, data interface{}) *Option {
 	case StringMapType:
 		opt.HelpArgName = "key=value"
 		opt.pStringM = data.(*map[string]string)
+	case DurationType:
+		opt.HelpArgName = "duration"
+		opt.pDur = data.(*time.Duration)
+	case DurationRepeatType:
+		opt.HelpArgName = "duration"
+		opt.pDurS = data.(*[]time.Duration)
+	case TimeType:
+		opt.HelpArgName = "time"
+		opt.TimeLayout = time.RFC3339
+		opt.pTime = data.(*time.Time)
+	case ValueType:
+		opt.pValue = data.(Value)
+		opt.HelpArgName = opt.pValue.Type()
+		opt.DefaultStr = opt.pValue.String()
 	case BoolType:
 		opt.pBool = data.(*bool)
 		opt.boolDefault = *data.(*bool)
@@ -113,11 +274,23 @@ func New(name string, optType Type, data interface{}) *Option {
 	return opt
 }
 
+// TypeName - Returns the name of the option type.
+// For custom Value types it returns the result of Value.Type.
+func (opt *Option) TypeName() string {
+	if opt.OptType == ValueType {
+		return opt.pValue.Type()
+	}
+	return opt.OptType.String()
+}
+
 func (opt *Option) synopsis() {
 	aliases := []string{}
 	for _, e := range opt.Aliases {
+		if opt.IsNegatedAlias(e) {
+			continue
+		}
 		if len(e) > 1 {
-			e = "--" + e
+			e = "--" + opt.negationPrefix + e
 		} else {
 			e = "-" + e
 		}
@@ -147,6 +320,17 @@ func (opt *Option) Value() interface{} {
 		return *opt.pFloat64
 	case StringMapType:
 		return *opt.pStringM
+	case DurationType:
+		return *opt.pDur
+	case DurationRepeatType:
+		return *opt.pDurS
+	case TimeType:
+		return *opt.pTime
+	case ValueType:
+		if g, ok := opt.pValue.(Getter); ok {
+			return g.Get()
+		}
+		return opt.pValue
 	default: // BoolType:
 		return *opt.pBool
 	}
@@ -159,6 +343,37 @@ func (opt *Option) SetAlias(alias ...string) *Option {
 	return opt
 }
 
+// SetNegatable - Adds a negated alias for every long alias of a bool option
+// using the given prefixes.
+// For example, with prefix "no-" the alias "color" gets the negated alias "no-color".
+// Returns the list of added aliases.
+func (opt *Option) SetNegatable(prefixes ...string) []string {
+	negated := []string{}
+	for _, prefix := range prefixes {
+		for _, alias := range opt.Aliases {
+			if len(alias) > 1 && !opt.IsNegatedAlias(alias) {
+				negated = append(negated, prefix+alias)
+			}
+		}
+	}
+	if len(prefixes) > 0 {
+		opt.negationPrefix = "[" + prefixes[0] + "]"
+	}
+	opt.NegatedAliases = append(opt.NegatedAliases, negated...)
+	opt.SetAlias(negated...)
+	return negated
+}
+
+// IsNegatedAlias - Indicates if the alias is one of the negated aliases.
+func (opt *Option) IsNegatedAlias(alias string) bool {
+	for _, e := range opt.NegatedAliases {
+		if e == alias {
+			return true
+		}
+	}
+	return false
+}
+
 // SetDescription - Updates the Description.
 func (opt *Option) SetDescription(s string) *Option {
 	opt.Description = s
@@ -178,6 +393,76 @@ func (opt *Option) SetDefaultStr(s string) *Option {
 	return opt
 }
 
+// SetTimeLayout - Updates the layout used to parse time options.
+func (opt *Option) SetTimeLayout(layout string) *Option {
+	opt.TimeLayout = layout
+	return opt
+}
+
+// SetValidValues - Limits the arguments the option accepts.
+// For StringMapType options it limits the keys.
+func (opt *Option) SetValidValues(values ...string) *Option {
+	opt.ValidValues = append(opt.ValidValues, values...)
+	return opt
+}
+
+// checkValidValues - Returns error if the argument is not one of the valid values.
+func (opt *Option) checkValidValues(arg string) error {
+	if len(opt.ValidValues) == 0 {
+		return nil
+	}
+	for _, v := range opt.ValidValues {
+		if arg == v {
+			return nil
+		}
+	}
+	return fmt.Errorf(text.ErrorArgumentNotValidValue, opt.UsedAlias, arg, opt.ValidValues)
+}
+
+// AddValidator - Adds checks to run after the option value is saved.
+func (opt *Option) AddValidator(v ...Validator) *Option {
+	opt.Validators = append(opt.Validators, v...)
+	return opt
+}
+
+// Validate - Runs the option validators in order and returns the first error.
+func (opt *Option) Validate() error {
+	for _, v := range opt.Validators {
+		if err := v(opt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckLength - Returns error if a called slice or map option has fewer or
+// more values than its length limits.
+func (opt *Option) CheckLength() error {
+	if !opt.Called {
+		return nil
+	}
+	var l int
+	switch opt.OptType {
+	case StringRepeatType:
+		l = len(*opt.pStringS)
+	case IntRepeatType:
+		l = len(*opt.pIntS)
+	case DurationRepeatType:
+		l = len(*opt.pDurS)
+	case StringMapType:
+		l = len(*opt.pStringM)
+	default:
+		return nil
+	}
+	if opt.MinLength > 0 && l < opt.MinLength {
+		return fmt.Errorf(text.ErrorTooFewValues, opt.UsedAlias, l, opt.MinLength)
+	}
+	if opt.MaxLength > 0 && l > opt.MaxLength {
+		return fmt.Errorf(text.ErrorTooManyValues, opt.UsedAlias, l, opt.MaxLength)
+	}
+	return nil
+}
+
 // SetRequired - Marks an option as required.
 func (opt *Option) SetRequired(msg string) *Option {
 	opt.IsRequired = true
@@ -185,6 +470,15 @@ func (opt *Option) SetRequired(msg string) *Option {
 	return opt
 }
 
+// SetDeprecated - Marks an option as deprecated.
+// replacement is the name of the option that replaces it, it can be empty.
+func (opt *Option) SetDeprecated(msg, replacement string) *Option {
+	opt.IsDeprecated = true
+	opt.DeprecatedMsg = msg
+	opt.ReplacedBy = replacement
+	return opt
+}
+
 // SetEnvVar - Sets the name of the Env var that sets the option's value.
 func (opt *Option) SetEnvVar(name string) *Option {
 	opt.EnvVar = name
@@ -195,19 +489,23 @@ func (opt *Option) SetEnvVar(name string) *Option {
 func (opt *Option) CheckRequired() error {
 	if opt.IsRequired {
 		if !opt.Called {
-			if opt.IsRequiredErr != "" {
-				return fmt.Errorf(opt.IsRequiredErr)
-			}
-			return fmt.Errorf(text.ErrorMissingRequiredOption, opt.Name)
+			return &RequiredOptionError{Name: opt.Name, Msg: opt.IsRequiredErr}
 		}
 	}
 	return nil
 }
 
-// SetCalled - Marks the option as called and records the alias used to call it.
+// SetCalled - Marks the option as called from the command line and records the alias used to call it.
 func (opt *Option) SetCalled(usedAlias string) *Option {
+	return opt.SetCalledFrom(CLISource, usedAlias)
+}
+
+// SetCalledFrom - Marks the option as called and records the source of the value.
+// usedAlias is the alias, environment variable or configuration key that set the value.
+func (opt *Option) SetCalledFrom(source Source, usedAlias string) *Option {
 	opt.Called = true
 	opt.UsedAlias = usedAlias
+	opt.Source = source
 	return opt
 }
 
@@ -246,6 +544,24 @@ func (opt *Option) SetFloat64(f float64) *Option {
 	return opt
 }
 
+// SetDuration - Set the option's data.
+func (opt *Option) SetDuration(d time.Duration) *Option {
+	*opt.pDur = d
+	return opt
+}
+
+// SetDurationSlice - Set the option's data.
+func (opt *Option) SetDurationSlice(s []time.Duration) *Option {
+	*opt.pDurS = s
+	return opt
+}
+
+// SetTime - Set the option's data.
+func (opt *Option) SetTime(t time.Time) *Option {
+	*opt.pTime = t
+	return opt
+}
+
 // SetStringSlice - Set the option's data.
 func (opt *Option) SetStringSlice(s []string) *Option {
 	*opt.pStringS = s
@@ -268,6 +584,116 @@ func (opt *Option) SetKeyValueToStringMap(k, v string) *Option {
 	return opt
 }
 
+// ClearValues - Removes the values of repeat and map options.
+// Used when the command line replaces the values set from other sources.
+func (opt *Option) ClearValues() *Option {
+	switch opt.OptType {
+	case StringRepeatType:
+		*opt.pStringS = []string{}
+	case IntRepeatType:
+		*opt.pIntS = []int{}
+	case DurationRepeatType:
+		*opt.pDurS = []time.Duration{}
+	case StringMapType:
+		// Delete the keys in place, StringMap returns the map itself.
+		for k := range *opt.pStringM {
+			delete(*opt.pStringM, k)
+		}
+	case ValueType:
+		if v, ok := opt.pValue.(Cloner); ok {
+			v.Clear()
+		}
+	}
+	return opt
+}
+
+// Clone - Returns a copy of the option with its own copy of the data.
+// Used to save and validate values before updating the option with CopyValue.
+// Custom Value types that don't implement Cloner can't be copied, the copy shares the Value.
+func (opt *Option) Clone() *Option {
+	c := *opt
+	switch opt.OptType {
+	case StringType:
+		v := *opt.pString
+		c.pString = &v
+	case IntType:
+		v := *opt.pInt
+		c.pInt = &v
+	case Float64Type:
+		v := *opt.pFloat64
+		c.pFloat64 = &v
+	case StringRepeatType:
+		v := append([]string{}, *opt.pStringS...)
+		c.pStringS = &v
+	case IntRepeatType:
+		v := append([]int{}, *opt.pIntS...)
+		c.pIntS = &v
+	case StringMapType:
+		v := map[string]string{}
+		for k, e := range *opt.pStringM {
+			v[k] = e
+		}
+		c.pStringM = &v
+	case DurationType:
+		v := *opt.pDur
+		c.pDur = &v
+	case DurationRepeatType:
+		v := append([]time.Duration{}, *opt.pDurS...)
+		c.pDurS = &v
+	case TimeType:
+		v := *opt.pTime
+		c.pTime = &v
+	case BoolType:
+		v := *opt.pBool
+		c.pBool = &v
+	case ValueType:
+		if v, ok := opt.pValue.(Cloner); ok {
+			c.pValue = v.Clone()
+		}
+	}
+	return &c
+}
+
+// CopyValue - Sets the option's data to the data of the given option of the same type.
+// Custom Value types are copied with Cloner when implemented, otherwise through their string representation,
+// unless both options share the Value.
+func (opt *Option) CopyValue(from *Option) error {
+	switch opt.OptType {
+	case StringType:
+		opt.SetString(*from.pString)
+	case IntType:
+		opt.SetInt(*from.pInt)
+	case Float64Type:
+		opt.SetFloat64(*from.pFloat64)
+	case StringRepeatType:
+		opt.SetStringSlice(append([]string{}, *from.pStringS...))
+	case IntRepeatType:
+		opt.SetIntSlice(append([]int{}, *from.pIntS...))
+	case StringMapType:
+		opt.ClearValues()
+		for k, v := range *from.pStringM {
+			(*opt.pStringM)[k] = v
+		}
+	case DurationType:
+		opt.SetDuration(*from.pDur)
+	case DurationRepeatType:
+		opt.SetDurationSlice(append([]time.Duration{}, *from.pDurS...))
+	case TimeType:
+		opt.SetTime(*from.pTime)
+	case ValueType:
+		if opt.pValue == from.pValue {
+			return nil
+		}
+		if v, ok := opt.pValue.(Cloner); ok {
+			return v.Copy(from.pValue)
+		}
+		return opt.pValue.Set(from.pValue.String())
+	default: // BoolType:
+		opt.SetBool(*from.pBool)
+	}
+	return nil
+}
+
 // Save - Saves the data provided into the option
 func (opt *Option) Save(a ...string) error {
 	if len(a) < 1 {
@@ -276,12 +702,15 @@ func (opt *Option) Save(a ...string) error {
 	Debug.Printf("name: %s, optType: %d\n", opt.Name, opt.OptType)
 	switch opt.OptType {
 	case StringType:
+		if err := opt.checkValidValues(a[0]); err != nil {
+			return err
+		}
 		opt.SetString(a[0])
 		return nil
 	case IntType:
 		i, err := strconv.Atoi(a[0])
 		if err != nil {
-			return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, a[0])
+			return opt.conversionError(a[0], "int")
 		}
 		opt.SetInt(i)
 		return nil
@@ -289,11 +718,16 @@ func (opt *Option) Save(a ...string) error {
 		// TODO: Read the different errors when parsing float
 		i, err := strconv.ParseFloat(a[0], 64)
 		if err != nil {
-			return fmt.Errorf(text.ErrorConvertToFloat64, opt.UsedAlias, a[0])
+			return opt.conversionError(a[0], "float64")
 		}
 		opt.SetFloat64(i)
 		return nil
 	case StringRepeatType:
+		for _, e := range a {
+			if err := opt.checkValidValues(e); err != nil {
+				return err
+			}
+		}
 		opt.SetStringSlice(append(*opt.pStringS, a...))
 		return nil
 	case IntRepeatType:
@@ -307,12 +741,12 @@ func (opt *Option) Save(a ...string) error {
 				in1, err := strconv.Atoi(n1)
 				if err != nil {
 					// TODO: Create new error description for this error.
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError(e, "int")
 				}
 				in2, err := strconv.Atoi(n2)
 				if err != nil {
 					// TODO: Create new error description for this error.
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError(e, "int")
 				}
 				if in1 < in2 {
 					for j := in1; j <= in2; j++ {
@@ -320,12 +754,12 @@ func (opt *Option) Save(a ...string) error {
 					}
 				} else {
 					// TODO: Create new error description for this error.
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError(e, "int")
 				}
 			} else {
 				i, err := strconv.Atoi(e)
 				if err != nil {
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError(e, "int")
 				}
 				is = append(is, i)
 			}
@@ -337,8 +771,46 @@ func (opt *Option) Save(a ...string) error {
 		if len(keyValue) < 2 {
 			return fmt.Errorf(text.ErrorArgumentIsNotKeyValue, opt.UsedAlias)
 		}
+		key := keyValue[0]
+		if opt.MapKeysToLower {
+			key = strings.ToLower(key)
+		}
+		if err := opt.checkValidValues(key); err != nil {
+			return err
+		}
 		opt.SetKeyValueToStringMap(keyValue[0], keyValue[1])
 		return nil
+	case DurationType:
+		d, err := time.ParseDuration(a[0])
+		if err != nil {
+			return opt.conversionError(a[0], "duration")
+		}
+		opt.SetDuration(d)
+		return nil
+	case DurationRepeatType:
+		var ds []time.Duration
+		for _, e := range a {
+			d, err := time.ParseDuration(e)
+			if err != nil {
+				return opt.conversionError(e, "duration")
+			}
+			ds = append(ds, d)
+		}
+		opt.SetDurationSlice(append(*opt.pDurS, ds...))
+		return nil
+	case TimeType:
+		t, err := time.Parse(opt.TimeLayout, a[0])
+		if err != nil {
+			return &ConversionError{Name: opt.Name, Alias: opt.UsedAlias, Arg: a[0], Type: "time", Layout: opt.TimeLayout}
+		}
+		opt.SetTime(t)
+		return nil
+	case ValueType:
+		err := opt.pValue.Set(a[0])
+		if err != nil {
+			return &ConversionError{Name: opt.Name, Alias: opt.UsedAlias, Arg: a[0], Type: opt.pValue.Type(), Err: err}
+		}
+		return nil
 	default: // BoolType:
 		if len(a) > 0 && a[0] == "true" {
 			opt.SetBool(true)
@@ -11,11 +11,51 @@ package option
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DavidGamba/go-getoptions/text"
 )
 
+type testValue string
+
+func (v *testValue) Set(s string) error {
+	if s == "" {
+		return fmt.Errorf("empty value")
+	}
+	*v = testValue(s)
+	return nil
+}
+
+func (v *testValue) String() string { return string(*v) }
+
+func (v *testValue) Type() string { return "test" }
+
+// testListValue - Cloner that accumulates its values.
+type testListValue []string
+
+func (v *testListValue) Set(s string) error {
+	*v = append(*v, s)
+	return nil
+}
+
+func (v *testListValue) String() string { return strings.Join(*v, ",") }
+
+func (v *testListValue) Type() string { return "list" }
+
+func (v *testListValue) Clone() Value {
+	c := append(testListValue{}, *v...)
+	return &c
+}
+
+func (v *testListValue) Copy(from Value) error {
+	*v = append(testListValue{}, *from.(*testListValue)...)
+	return nil
+}
+
+func (v *testListValue) Clear() { *v = testListValue{} }
+
 func TestOption(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -172,6 +212,74 @@ func TestOption(t *testing.T) {
 			return New("help", StringMapType, &m)
 		}(), []string{"hola"}, map[string]string{},
 			fmt.Errorf(text.ErrorArgumentIsNotKeyValue, "")},
+
+		{"duration", func() *Option {
+			d := time.Duration(0)
+			return New("help", DurationType, &d)
+		}(), []string{"1m30s"}, 90 * time.Second, nil},
+		{"duration error", func() *Option {
+			d := time.Duration(0)
+			return New("help", DurationType, &d).SetCalled("d")
+		}(), []string{"123x"}, time.Duration(0),
+			fmt.Errorf(text.ErrorConvertToDuration, "d", "123x")},
+		{"duration slice", func() *Option {
+			ds := []time.Duration{}
+			return New("help", DurationRepeatType, &ds)
+		}(), []string{"1s", "2m"}, []time.Duration{time.Second, 2 * time.Minute}, nil},
+		{"duration slice error", func() *Option {
+			ds := []time.Duration{}
+			return New("help", DurationRepeatType, &ds)
+		}(), []string{"1s", "x"}, []time.Duration{},
+			fmt.Errorf(text.ErrorConvertToDuration, "", "x")},
+		{"time", func() *Option {
+			tt := time.Time{}
+			return New("help", TimeType, &tt)
+		}(), []string{"2021-02-03T04:05:06Z"}, time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC), nil},
+		{"time layout", func() *Option {
+			tt := time.Time{}
+			return New("help", TimeType, &tt).SetTimeLayout("2006-01-02")
+		}(), []string{"2021-02-03"}, time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC), nil},
+		{"time error", func() *Option {
+			tt := time.Time{}
+			return New("help", TimeType, &tt).SetTimeLayout("2006-01-02")
+		}(), []string{"2021"}, time.Time{},
+			fmt.Errorf(text.ErrorConvertToTime, "", "2021", "2006-01-02")},
+
+		{"string valid values", func() *Option {
+			s := ""
+			return New("help", StringType, &s).SetValidValues("json", "yaml")
+		}(), []string{"yaml"}, "yaml", nil},
+		{"string valid values error", func() *Option {
+			s := ""
+			return New("help", StringType, &s).SetValidValues("json", "yaml").SetCalled("h")
+		}(), []string{"xml"}, "",
+			fmt.Errorf(text.ErrorArgumentNotValidValue, "h", "xml", []string{"json", "yaml"})},
+		{"string slice valid values error", func() *Option {
+			ss := []string{}
+			return New("help", StringRepeatType, &ss).SetValidValues("a", "b")
+		}(), []string{"a", "c"}, []string{},
+			fmt.Errorf(text.ErrorArgumentNotValidValue, "", "c", []string{"a", "b"})},
+		{"map valid keys", func() *Option {
+			m := make(map[string]string)
+			opt := New("help", StringMapType, &m).SetValidValues("key")
+			opt.MapKeysToLower = true
+			return opt
+		}(), []string{"KEY=value"}, map[string]string{"key": "value"}, nil},
+		{"map valid keys error", func() *Option {
+			m := make(map[string]string)
+			return New("help", StringMapType, &m).SetValidValues("key")
+		}(), []string{"other=value"}, map[string]string{},
+			fmt.Errorf(text.ErrorArgumentNotValidValue, "", "other", []string{"key"})},
+
+		{"value", func() *Option {
+			v := testValue("")
+			return New("help", ValueType, &v)
+		}(), []string{"hola"}, func() Value { v := testValue("hola"); return &v }(), nil},
+		{"value error", func() *Option {
+			v := testValue("")
+			return New("help", ValueType, &v)
+		}(), []string{""}, func() Value { v := testValue(""); return &v }(),
+			fmt.Errorf(text.ErrorConvertToValue, "", "test", "", "empty value")},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -289,3 +397,267 @@ func TestOther(t *testing.T) {
 		t.Errorf("got = '%#v', want '%#v'", opt.HelpSynopsis, "--help <int>...")
 	}
 }
+
+func TestValidate(t *testing.T) {
+	i := 0
+	opt := New("help", IntType, &i).AddValidator(func(opt *Option) error {
+		if opt.Int() > 1 {
+			return fmt.Errorf("too big")
+		}
+		return nil
+	})
+	if err := opt.Validate(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	opt.SetInt(2)
+	if err := opt.Validate(); err == nil || err.Error() != "too big" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCheckLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		option *Option
+		err    error
+	}{
+		{"not called", func() *Option {
+			ss := []string{}
+			opt := New("help", StringRepeatType, &ss)
+			opt.MinLength = 1
+			return opt
+		}(), nil},
+		{"not slice", func() *Option {
+			i := 0
+			opt := New("help", IntType, &i).SetCalled("h")
+			opt.MinLength = 1
+			return opt
+		}(), nil},
+		{"int slice", func() *Option {
+			ii := []int{1}
+			opt := New("help", IntRepeatType, &ii).SetCalled("h")
+			opt.MinLength = 2
+			return opt
+		}(), fmt.Errorf(text.ErrorTooFewValues, "h", 1, 2)},
+		{"duration slice", func() *Option {
+			ds := []time.Duration{1, 2}
+			opt := New("help", DurationRepeatType, &ds).SetCalled("h")
+			opt.MaxLength = 1
+			return opt
+		}(), fmt.Errorf(text.ErrorTooManyValues, "h", 2, 1)},
+		{"map", func() *Option {
+			m := map[string]string{"a": "b"}
+			opt := New("help", StringMapType, &m).SetCalled("h")
+			opt.MinLength = 1
+			opt.MaxLength = 1
+			return opt
+		}(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.option.CheckLength()
+			if !reflect.DeepEqual(err, tt.err) {
+				t.Errorf("got = '%#v', want '%#v'", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		source   Source
+		expected string
+	}{
+		{DefaultSource, "default"},
+		{CLISource, "cli"},
+		{EnvSource, "env"},
+		{ConfigSource, "config"},
+		{ProgrammaticSource, "programmatic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if tt.source.String() != tt.expected {
+				t.Errorf("Unexpected source string: %s", tt.source)
+			}
+		})
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		completion Completion
+		expected   string
+	}{
+		{NoCompletion, "none"},
+		{FileCompletion, "file"},
+		{DirCompletion, "dir"},
+		{ListCompletion, "list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if tt.completion.String() != tt.expected {
+				t.Errorf("Unexpected completion string: %s", tt.completion)
+			}
+		})
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		opt      func() *Option
+		expected string
+	}{
+		{func() *Option { b := false; return New("a", BoolType, &b) }, "bool"},
+		{func() *Option { s := ""; return New("a", StringType, &s) }, "string"},
+		{func() *Option { i := 0; return New("a", IntType, &i) }, "int"},
+		{func() *Option { f := 0.0; return New("a", Float64Type, &f) }, "float64"},
+		{func() *Option { ss := []string{}; return New("a", StringRepeatType, &ss) }, "[]string"},
+		{func() *Option { is := []int{}; return New("a", IntRepeatType, &is) }, "[]int"},
+		{func() *Option { m := map[string]string{}; return New("a", StringMapType, &m) }, "map[string]string"},
+		{func() *Option { d := time.Duration(0); return New("a", DurationType, &d) }, "duration"},
+		{func() *Option { ds := []time.Duration{}; return New("a", DurationRepeatType, &ds) }, "[]duration"},
+		{func() *Option { tm := time.Time{}; return New("a", TimeType, &tm) }, "time"},
+		{func() *Option { v := testValue(""); return New("a", ValueType, &v) }, "test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if name := tt.opt().TypeName(); name != tt.expected {
+				t.Errorf("Unexpected type name: %s", name)
+			}
+		})
+	}
+	if ValueType.String() != "value" || Type(-1).String() != "unknown" {
+		t.Errorf("Unexpected type strings: %s, %s", ValueType, Type(-1))
+	}
+}
+
+func TestCopyValue(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		from func() *Option
+		to   func() *Option
+	}{
+		{"bool", func() *Option { b := true; return New("a", BoolType, &b) },
+			func() *Option { b := false; return New("b", BoolType, &b) }},
+		{"string", func() *Option { s := "x"; return New("a", StringType, &s) },
+			func() *Option { s := ""; return New("b", StringType, &s) }},
+		{"int", func() *Option { i := 1; return New("a", IntType, &i) },
+			func() *Option { i := 0; return New("b", IntType, &i) }},
+		{"float64", func() *Option { f := 1.5; return New("a", Float64Type, &f) },
+			func() *Option { f := 0.0; return New("b", Float64Type, &f) }},
+		{"string slice", func() *Option { s := []string{"x", "y"}; return New("a", StringRepeatType, &s) },
+			func() *Option { s := []string{"z"}; return New("b", StringRepeatType, &s) }},
+		{"int slice", func() *Option { s := []int{1, 2}; return New("a", IntRepeatType, &s) },
+			func() *Option { s := []int{3}; return New("b", IntRepeatType, &s) }},
+		{"string map", func() *Option { m := map[string]string{"k": "v"}; return New("a", StringMapType, &m) },
+			func() *Option { m := map[string]string{"x": "y"}; return New("b", StringMapType, &m) }},
+		{"duration", func() *Option { d := time.Second; return New("a", DurationType, &d) },
+			func() *Option { d := time.Duration(0); return New("b", DurationType, &d) }},
+		{"duration slice", func() *Option { d := []time.Duration{time.Second}; return New("a", DurationRepeatType, &d) },
+			func() *Option { d := []time.Duration{}; return New("b", DurationRepeatType, &d) }},
+		{"time", func() *Option { return New("a", TimeType, &now) },
+			func() *Option { tm := time.Time{}; return New("b", TimeType, &tm) }},
+		{"value", func() *Option { v := testValue("x"); return New("a", ValueType, &v) },
+			func() *Option { v := testValue("y"); return New("b", ValueType, &v) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := tt.from(), tt.to()
+			err := to.CopyValue(from)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(to.Value(), from.Value()) {
+				t.Errorf("Value didn't match: %v != %v", to.Value(), from.Value())
+			}
+		})
+	}
+
+	t.Run("value error", func(t *testing.T) {
+		v, w := testValue(""), testValue("y")
+		err := New("b", ValueType, &w).CopyValue(New("a", ValueType, &v))
+		if err == nil || err.Error() != "empty value" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClone(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		opt  func() *Option
+		arg  string
+	}{
+		{"bool", func() *Option { b := false; return New("a", BoolType, &b) }, "true"},
+		{"string", func() *Option { s := "x"; return New("a", StringType, &s) }, "y"},
+		{"int", func() *Option { i := 1; return New("a", IntType, &i) }, "2"},
+		{"float64", func() *Option { f := 1.5; return New("a", Float64Type, &f) }, "2.5"},
+		{"string slice", func() *Option { s := []string{"x"}; return New("a", StringRepeatType, &s) }, "y"},
+		{"int slice", func() *Option { s := []int{1}; return New("a", IntRepeatType, &s) }, "2"},
+		{"string map", func() *Option { m := map[string]string{"k": "v"}; return New("a", StringMapType, &m) }, "x=y"},
+		{"duration", func() *Option { d := time.Second; return New("a", DurationType, &d) }, "1m"},
+		{"duration slice", func() *Option { d := []time.Duration{time.Second}; return New("a", DurationRepeatType, &d) }, "1m"},
+		{"time", func() *Option { tm := now; return New("a", TimeType, &tm) }, "2021-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := tt.opt()
+			before := fmt.Sprint(opt.Value())
+			c := opt.Clone()
+			err := c.Save(tt.arg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if fmt.Sprint(opt.Value()) != before {
+				t.Errorf("Unexpected value: %v", opt.Value())
+			}
+			err = opt.CopyValue(c)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(opt.Value(), c.Value()) || fmt.Sprint(opt.Value()) == before {
+				t.Errorf("Value didn't match: %v != %v", opt.Value(), c.Value())
+			}
+		})
+	}
+
+	t.Run("value", func(t *testing.T) {
+		v := testValue("x")
+		opt := New("a", ValueType, &v)
+		c := opt.Clone()
+		err := c.Save("y")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.CopyValue(c)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if v != "y" {
+			t.Errorf("Unexpected value: %s", v)
+		}
+	})
+
+	t.Run("cloner", func(t *testing.T) {
+		v := testListValue{"x"}
+		opt := New("a", ValueType, &v)
+		c := opt.Clone()
+		c.ClearValues()
+		err := c.Save("y")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(v, testListValue{"x"}) {
+			t.Errorf("Unexpected value: %v", v)
+		}
+		err = opt.CopyValue(c)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(v, testListValue{"y"}) {
+			t.Errorf("Unexpected value: %v", v)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// maxSuggestions - Maximum amount of suggestions shown in an error.
+const maxSuggestions = 3
+
+// DisableSuggestions - Don't add "did you mean" suggestions to unknown option and command errors.
+// Commands inherit the setting of their parent.
+func (gopt *GetOpt) DisableSuggestions() *GetOpt {
+	gopt.noSuggestions = true
+	return gopt
+}
+
+func (gopt *GetOpt) suggestionsDisabled() bool {
+	for g := gopt; g != nil; g = g.parent {
+		if g.noSuggestions {
+			return true
+		}
+	}
+	return false
+}
+
+// optionSuggestions - Returns the aliases similar to the unknown option.
+// The aliases of the parent options are considered as well, hidden and deprecated options are not.
+func (gopt *GetOpt) optionSuggestions(alias string) []string {
+	if gopt.suggestionsDisabled() {
+		return []string{}
+	}
+	candidates := []string{}
+	for g := gopt; g != nil; g = g.parent {
+		for _, opt := range g.obj {
+			if opt.IsHidden || opt.IsDeprecated {
+				continue
+			}
+			candidates = append(candidates, opt.Aliases...)
+		}
+	}
+	return suggestions(alias, candidates, gopt.isCaseInsensitive())
+}
+
+// commandSuggestions - Returns the command names similar to the unknown command.
+// Hidden and deprecated commands are not considered.
+func (gopt *GetOpt) commandSuggestions(name string) []string {
+	if gopt.suggestionsDisabled() {
+		return []string{}
+	}
+	candidates := []string{}
+	for n, command := range gopt.commands {
+		if command.hidden || command.deprecated {
+			continue
+		}
+		candidates = append(candidates, n)
+	}
+	return suggestions(name, candidates, gopt.isCaseInsensitive())
+}
+
+func didYouMean(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(text.MessageDidYouMean, strings.Join(list, text.MessageDidYouMeanSeparator))
+}
+
+// suggestions - Returns the candidates closest to s, sorted by edit distance and name.
+// Single character strings are not considered, every single character alias would be a suggestion.
+func suggestions(s string, candidates []string, ignoreCase bool) []string {
+	if len(s) <= 1 {
+		return []string{}
+	}
+	// Allow one edit for every 3 characters.
+	limit := len(s) / 3
+	if limit < 1 {
+		limit = 1
+	}
+	distances := map[string]int{}
+	for _, c := range candidates {
+		if len(c) <= 1 {
+			continue
+		}
+		a, b := s, c
+		if ignoreCase {
+			a, b = strings.ToLower(a), strings.ToLower(b)
+		}
+		if d := editDistance(a, b); d <= limit {
+			distances[c] = d
+		}
+	}
+	list := []string{}
+	for c := range distances {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if distances[list[i]] != distances[list[j]] {
+			return distances[list[i]] < distances[list[j]]
+		}
+		return list[i] < list[j]
+	})
+	if len(list) > maxSuggestions {
+		list = list[:maxSuggestions]
+	}
+	return list
+}
+
+// editDistance - Optimal string alignment distance, a Levenshtein distance that counts transpositions as a single edit.
+// For example, the distance between verison and version is 1.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, minInt(d[i][j-1]+1, d[i-1][j-1]+cost))
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
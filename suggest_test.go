@@ -0,0 +1,163 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestSuggestions(t *testing.T) {
+	setup := func() (*GetOpt, *GetOpt) {
+		opt := New()
+		opt.Bool("version", false, opt.Alias("V"))
+		opt.Bool("verbose", false)
+		opt.Bool("color", false, opt.Negatable())
+		opt.String("profile", "")
+		cmd := opt.NewCommand("deploy", "")
+		cmd.String("region", "")
+		cmd.SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		opt.NewCommand("delete", "")
+		return opt, cmd
+	}
+	tests := []struct {
+		name string
+		fn   func() error
+		err  string
+	}{
+		{"transposition", func() error {
+			opt, _ := setup()
+			_, err := opt.Parse([]string{"--verison"})
+			return err
+		}, "Unknown option '--verison', did you mean '--version'?"},
+		{"several", func() error {
+			opt, _ := setup()
+			_, err := opt.Parse([]string{"--versoe"})
+			return err
+		}, "Unknown option '--versoe', did you mean '--verbose' or '--version'?"},
+		{"max", func() error {
+			opt := New()
+			for _, name := range []string{"abd", "abe", "abf", "abg"} {
+				opt.Bool(name, false)
+			}
+			_, err := opt.Parse([]string{"--abc"})
+			return err
+		}, "Unknown option '--abc', did you mean '--abd' or '--abe' or '--abf'?"},
+		{"negated", func() error {
+			opt, _ := setup()
+			_, err := opt.Parse([]string{"--no-colr"})
+			return err
+		}, "Unknown option '--no-colr', did you mean '--no-color'?"},
+		{"inherited", func() error {
+			_, cmd := setup()
+			_, err := cmd.Parse([]string{"--profle"})
+			return err
+		}, "Unknown option '--profle', did you mean '--profile'?"},
+		{"none", func() error {
+			opt, _ := setup()
+			_, err := opt.Parse([]string{"--xyz"})
+			return err
+		}, "Unknown option 'xyz'"},
+		{"single char", func() error {
+			opt, _ := setup()
+			_, err := opt.Parse([]string{"-W"})
+			return err
+		}, "Unknown option 'W'"},
+		{"single dash", func() error {
+			opt, _ := setup()
+			_, err := opt.Parse([]string{"-verison"})
+			return err
+		}, "Unknown option '-verison', did you mean '--version'?"},
+		{"single dash mode", func() error {
+			opt, _ := setup()
+			opt.SetMode(SingleDash)
+			_, err := opt.Parse([]string{"--verison"})
+			return err
+		}, "Unknown option '--verison', did you mean '--version'?"},
+		{"suggestion dash", func() error {
+			return &UnknownOptionError{Alias: "vv", Dash: "-", Suggestions: []string{"v", "vvv"}}
+		}, "Unknown option '-vv', did you mean '-v' or '--vvv'?"},
+		{"case insensitive", func() error {
+			opt, _ := setup()
+			opt.SetCaseInsensitive()
+			_, err := opt.Parse([]string{"--VERISON"})
+			return err
+		}, "Unknown option '--VERISON', did you mean '--version'?"},
+		{"disabled", func() error {
+			opt, cmd := setup()
+			opt.DisableSuggestions()
+			_, err := cmd.Parse([]string{"--profle"})
+			return err
+		}, "Unknown option 'profle'"},
+		{"command", func() error {
+			opt, _ := setup()
+			return opt.Dispatch(context.Background(), "help", []string{"deplyo"})
+		}, "not a command: 'deplyo', did you mean 'deploy'?"},
+		{"commands", func() error {
+			opt, _ := setup()
+			return opt.Dispatch(context.Background(), "help", []string{"deleoy"})
+		}, "not a command: 'deleoy', did you mean 'delete' or 'deploy'?"},
+		{"command disabled", func() error {
+			opt, _ := setup()
+			opt.DisableSuggestions()
+			return opt.Dispatch(context.Background(), "help", []string{"deplyo"})
+		}, "not a command: 'deplyo'"},
+		{"help", func() error {
+			opt, _ := setup()
+			return opt.Dispatch(context.Background(), "help", []string{"help", "deplyo"})
+		}, "unkown help entry 'deplyo', did you mean 'deploy'?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fn()
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Error string didn't match expected value: %v", err)
+			}
+		})
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		opt, _ := setup()
+		opt.SetUnknownMode(Warn)
+		buf := new(bytes.Buffer)
+		opt.Writer = buf
+		_, err := opt.Parse([]string{"--verison"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if buf.String() != "WARNING: Unknown option '--verison', did you mean '--version'?\n" {
+			t.Errorf("Unexpected warning: %q", buf.String())
+		}
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"version", "version", 0},
+		{"verison", "version", 1},
+		{"verson", "version", 1},
+		{"versions", "version", 1},
+		{"kitten", "sitting", 3},
+		{"ñandú", "nandu", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"-"+tt.b, func(t *testing.T) {
+			if got := editDistance(tt.a, tt.b); got != tt.expected {
+				t.Errorf("editDistance(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
@@ -13,6 +13,10 @@ package text
 // It has a string placeholder '%s' for the name of the option missing the argument.
 var ErrorMissingArgument = "Missing argument for option '%s'!"
 
+// MessageOnAbbreviation holds the text for the warning when an option is abbreviated.
+// It has a string placeholder '%s' for the passed option and another one for the full alias.
+var MessageOnAbbreviation = "Option '%s' is an abbreviation of '%s'"
+
 // ErrorAmbiguousArgument holds the text for ambiguous argument error.
 // It has a string placeholder '%s' for the passed option and a []string list of matches.
 var ErrorAmbiguousArgument = "Ambiguous option '%s', matches %v!"
@@ -21,10 +25,26 @@ var ErrorAmbiguousArgument = "Ambiguous option '%s', matches %v!"
 // It has a string placeholder '%s' for the name of the missing option.
 var ErrorMissingRequiredOption = "Missing required option '%s'!"
 
+// ErrorMutuallyExclusiveOptions holds the text for the error when options that are mutually exclusive are called together.
+// It has two string placeholders ('%s') for the names of the conflicting options.
+var ErrorMutuallyExclusiveOptions = "Option '%s' can't be used together with option '%s'!"
+
+// ErrorOptionRequiresOption holds the text for the error when an option is called without an option it requires.
+// It has two string placeholders ('%s'). The first one for the name of the called option and the second one for the name of the missing option.
+var ErrorOptionRequiresOption = "Option '%s' requires option '%s'!"
+
+// ErrorMissingOneOfRequiredOptions holds the text for the error when none of a group of options was called.
+// It has a []string placeholder ('%v') for the list of option names.
+var ErrorMissingOneOfRequiredOptions = "Missing required option, one of %v!"
+
 // ErrorArgumentIsNotKeyValue holds the text for Map type options where the argument is not of key=value type.
 // It has a string placeholder '%s' for the name of the option missing the argument.
 var ErrorArgumentIsNotKeyValue = "Argument error for option '%s': Should be of type 'key=value'!"
 
+// ErrorArgumentNotValidValue holds the text for options that only accept a fixed set of arguments.
+// It has two string placeholders ('%s') and a []string list. The first one for the name of the option, the second one for the argument that is not valid and the list of valid values.
+var ErrorArgumentNotValidValue = "Argument error for option '%s': Invalid argument '%s', valid values: %v"
+
 // ErrorArgumentWithDash holds the text for missing argument error in cases where the next argument looks like an option (starts with '-').
 // It has a string placeholder '%s' for the name of the option missing the argument.
 var ErrorArgumentWithDash = "Missing argument for option '%s'!\n" +
@@ -38,13 +58,130 @@ var ErrorConvertToInt = "Argument error for option '%s': Can't convert string to
 // It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
 var ErrorConvertToFloat64 = "Argument error for option '%s': Can't convert string to float64: '%s'"
 
+// ErrorConvertToDuration holds the text for Duration Conversion argument error.
+// It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
+var ErrorConvertToDuration = "Argument error for option '%s': Can't convert string to duration: '%s'"
+
+// ErrorConvertToTime holds the text for Time Conversion argument error.
+// It has three string placeholders ('%s'). The first one for the name of the option with the wrong argument, the second one for the argument that could not be converted and the last one for the expected layout.
+var ErrorConvertToTime = "Argument error for option '%s': Can't convert string to time: '%s', expected layout '%s'"
+
+// ErrorConvertToValue holds the text for custom Value type Conversion argument error.
+// It has four placeholders. The first one for the name of the option with the wrong argument, the second one for the name of the type, the third one for the argument that could not be converted and the last one for the error returned by the Value.
+var ErrorConvertToValue = "Argument error for option '%s': Can't convert string to %s: '%s': %s"
+
+// ErrorValueLessThanMin holds the text for options with a value lower than the allowed minimum.
+// It has a string placeholder '%s' for the name of the option and two value placeholders ('%v') for the value and the minimum.
+var ErrorValueLessThanMin = "Argument error for option '%s': '%v' is less than the minimum of '%v'"
+
+// ErrorValueGreaterThanMax holds the text for options with a value greater than the allowed maximum.
+// It has a string placeholder '%s' for the name of the option and two value placeholders ('%v') for the value and the maximum.
+var ErrorValueGreaterThanMax = "Argument error for option '%s': '%v' is greater than the maximum of '%v'"
+
+// ErrorValueDoesNotMatch holds the text for options with a value that doesn't match the required pattern.
+// It has three string placeholders ('%s'). The first one for the name of the option, the second one for the value and the last one for the pattern.
+var ErrorValueDoesNotMatch = "Argument error for option '%s': '%s' doesn't match the pattern '%s'"
+
+// ErrorTooFewValues holds the text for slice and map options with fewer values than the allowed minimum.
+// It has a string placeholder '%s' for the name of the option and two int placeholders ('%d') for the amount of values and the minimum.
+var ErrorTooFewValues = "Argument error for option '%s': Got %d values, expected at least %d"
+
+// ErrorTooManyValues holds the text for slice and map options with more values than the allowed maximum.
+// It has a string placeholder '%s' for the name of the option and two int placeholders ('%d') for the amount of values and the maximum.
+var ErrorTooManyValues = "Argument error for option '%s': Got %d values, expected at most %d"
+
+// ErrorValidation holds the text for errors returned by custom validation functions.
+// It has two string placeholders ('%s'). The first one for the name of the option and the second one for the error returned by the validation function.
+var ErrorValidation = "Argument error for option '%s': %s"
+
+// ErrorMissingRequiredArgument holds the text for missing required positional argument error.
+// It has a string placeholder '%s' for the name of the missing argument.
+var ErrorMissingRequiredArgument = "Missing required argument '%s'!"
+
+// ErrorTooFewArguments holds the text for the error when fewer positional arguments than the allowed minimum are given.
+// It has two int placeholders ('%d') for the amount of arguments and the minimum.
+var ErrorTooFewArguments = "Got %d arguments, expected at least %d!"
+
+// ErrorTooManyArguments holds the text for the error when more positional arguments than the allowed maximum are given.
+// It has two int placeholders ('%d') for the amount of arguments and the maximum.
+var ErrorTooManyArguments = "Got %d arguments, expected at most %d!"
+
+// ErrorDeprecatedReplacementUndefined holds the text for the error when the replacement of a deprecated option is not defined.
+// It has two string placeholders ('%s'). The first one for the name of the deprecated option and the second one for the name of the replacement.
+var ErrorDeprecatedReplacementUndefined = "Deprecated option '%s' replacement '%s' is not defined"
+
+// ErrorDeprecatedReplacementType holds the text for the error when a deprecated option and its replacement have different types.
+// It has two string placeholders ('%s'). The first one for the name of the deprecated option and the second one for the name of the replacement.
+var ErrorDeprecatedReplacementType = "Deprecated option '%s' and its replacement '%s' have different types"
+
+// ErrorConfigParse holds the text for configuration file parsing errors.
+// It has two string placeholders ('%s'). The first one for the configuration source and the second one for the parsing error.
+var ErrorConfigParse = "Error parsing config file '%s': %s"
+
+// ErrorConfigUnknownKey holds the text for unknown keys in configuration files.
+// It has two string placeholders ('%s'). The first one for the key and the second one for the configuration source.
+var ErrorConfigUnknownKey = "Unknown key '%s' in config file '%s'"
+
+// ErrorConfigInvalidValue holds the text for configuration values that can't be assigned to an option or command.
+// It has two string placeholders ('%s'). The first one for the key and the second one for the configuration source.
+var ErrorConfigInvalidValue = "Invalid value for key '%s' in config file '%s'"
+
+// ErrorAmbiguousCommand holds the text for the error when the argument matches more than one command ignoring case.
+// It has a string placeholder '%s' for the argument and a []string list of matches.
+var ErrorAmbiguousCommand = "Ambiguous command '%s', matches %v!"
+
+// ErrorUnknownCommand holds the text for the error when the first argument is not a command.
+// It has a string placeholder '%s' for the argument.
+var ErrorUnknownCommand = "not a command: '%s'"
+
+// ErrorUnknownCommandOrOption holds the text for the error when the first argument looks like an option but it is not a command or a valid option.
+// It has a string placeholder '%s' for the argument.
+var ErrorUnknownCommandOrOption = "not a command or a valid option: '%s'\n       Did you mean to pass it after the command?"
+
+// ErrorUnknownHelpEntry holds the text for the error when the help command is called with an unknown command.
+// It has a string placeholder '%s' for the command name.
+var ErrorUnknownHelpEntry = "unkown help entry '%s'"
+
 // MessageOnUnknown holds the text for the unknown option message.
 // It has a string placeholder '%s' for the name of the option missing the argument.
 var MessageOnUnknown = "Unknown option '%s'"
 
+// MessageOnDeprecatedOption holds the text for the warning when a deprecated option is used.
+// It has a string placeholder '%s' for the name of the option.
+var MessageOnDeprecatedOption = "Option '%s' is deprecated"
+
+// MessageOnDeprecatedCommand holds the text for the warning when a deprecated command is used.
+// It has a string placeholder '%s' for the name of the command.
+var MessageOnDeprecatedCommand = "Command '%s' is deprecated"
+
+// MessageDeprecatedReplacement holds the text appended to deprecation warnings when there is a replacement.
+// It has a string placeholder '%s' for the replacement.
+var MessageDeprecatedReplacement = ", use '%s' instead"
+
+// MessageDidYouMean holds the text appended to unknown option and command errors when there are similar names.
+// It has a string placeholder '%s' for the list of suggestions.
+var MessageDidYouMean = ", did you mean %s?"
+
+// MessageDidYouMeanSeparator holds the text used to join the list of suggestions.
+var MessageDidYouMeanSeparator = " or "
+
 // MessageOnInterrupt holds the text for the message to be printed when an interrupt is received.
 var MessageOnInterrupt = "Interrupt signal received"
 
+// DescribeOptionDescription holds the description of the option defined with DescribeOption
+var DescribeOptionDescription = "Print the description of the program options and commands in JSON format."
+
+// CompletionCommandDescription holds the description of the command defined with CompletionCommand
+var CompletionCommandDescription = "Print the shell completion script."
+
+// CompletionShellDescription holds the description of the shell argument of the command defined with CompletionCommand.
+// It has two string placeholders ('%[1]s' and '%[2]s') for the program name and the command name.
+var CompletionShellDescription = `Shell to generate the completion script for: bash, zsh or fish.
+Installation:
+  bash: add 'source <(%[1]s %[2]s bash)' to ~/.bashrc
+  zsh:  %[1]s %[2]s zsh > "${fpath[1]}/_%[1]s"
+  fish: %[1]s %[2]s fish > ~/.config/fish/completions/%[1]s.fish`
+
 // HelpNameHeader holds the header text for the command name
 var HelpNameHeader = "NAME"
 
@@ -54,8 +191,47 @@ var HelpSynopsisHeader = "SYNOPSIS"
 // HelpCommandsHeader holds the header text for the command list
 var HelpCommandsHeader = "COMMANDS"
 
+// HelpArgumentsHeader holds the header text for the positional argument list
+var HelpArgumentsHeader = "ARGUMENTS"
+
 // HelpRequiredOptionsHeader holds the header text for the required parameters
 var HelpRequiredOptionsHeader = "REQUIRED PARAMETERS"
 
 // HelpOptionsHeader holds the header text for the option list
 var HelpOptionsHeader = "OPTIONS"
+
+// HelpDescriptionHeader holds the header text for the command description in generated documentation
+var HelpDescriptionHeader = "DESCRIPTION"
+
+// HelpEnvironmentHeader holds the header text for the environment variable list in generated documentation
+var HelpEnvironmentHeader = "ENVIRONMENT"
+
+// HelpSeeAlsoHeader holds the header text for the related command list in generated documentation
+var HelpSeeAlsoHeader = "SEE ALSO"
+
+// HelpGlobalOptionsHeader holds the header text for the option list inherited from the parent command
+var HelpGlobalOptionsHeader = "GLOBAL OPTIONS"
+
+// DocOptionColumn holds the header text for the option column of the option table in generated documentation
+var DocOptionColumn = "Option"
+
+// DocAliasesColumn holds the header text for the aliases column of the option table in generated documentation
+var DocAliasesColumn = "Aliases"
+
+// DocTypeColumn holds the header text for the type column of the option table in generated documentation
+var DocTypeColumn = "Type"
+
+// DocDefaultColumn holds the header text for the default column of the option table in generated documentation
+var DocDefaultColumn = "Default"
+
+// DocEnvColumn holds the header text for the environment variable column of the option table in generated documentation
+var DocEnvColumn = "Env"
+
+// DocRequiredColumn holds the header text for the required column of the option table in generated documentation
+var DocRequiredColumn = "Required"
+
+// DocDescriptionColumn holds the header text for the description column of the option table in generated documentation
+var DocDescriptionColumn = "Description"
+
+// DocRequiredValue holds the text shown in the required column for required options
+var DocRequiredValue = "yes"
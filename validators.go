@@ -0,0 +1,215 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/DavidGamba/go-getoptions/option"
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+// Validators run after the option value is saved, both for values passed on
+// the command line and for values read with GetEnv.
+// Errors name the alias or environment variable used to set the option.
+
+// Min - Returns an error if a numeric option is given a value lower than min.
+// For slices every element is checked.
+// Values are compared in their own type, so large int64 and uint64 values are not rounded.
+//
+// Supported by Int, Float64, IntSlice and the numeric generic types.
+// It will *panic* for Duration options, use Validate instead.
+func (gopt *GetOpt) Min(min float64) ModifyFn {
+	return func(opt *option.Option) {
+		failIfDuration(opt, "Min")
+		opt.AddValidator(func(opt *option.Option) error {
+			for _, n := range numericValues(opt.Value()) {
+				if compareNumber(n, min) < 0 {
+					return fmt.Errorf(text.ErrorValueLessThanMin, opt.UsedAlias, n.Interface(), min)
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// Max - Returns an error if a numeric option is given a value greater than max.
+// For slices every element is checked.
+// Values are compared in their own type, so large int64 and uint64 values are not rounded.
+//
+// Supported by Int, Float64, IntSlice and the numeric generic types.
+// It will *panic* for Duration options, use Validate instead.
+func (gopt *GetOpt) Max(max float64) ModifyFn {
+	return func(opt *option.Option) {
+		failIfDuration(opt, "Max")
+		opt.AddValidator(func(opt *option.Option) error {
+			for _, n := range numericValues(opt.Value()) {
+				if compareNumber(n, max) > 0 {
+					return fmt.Errorf(text.ErrorValueGreaterThanMax, opt.UsedAlias, n.Interface(), max)
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// failIfDuration will *panic* if the option is a Duration option.
+// Durations are int64 nanoseconds, a numeric limit would be ambiguous.
+func failIfDuration(opt *option.Option, name string) {
+	if opt.OptType == option.DurationType || opt.OptType == option.DurationRepeatType {
+		panic(fmt.Sprintf("%s is not supported by duration option '%s'", name, opt.Name))
+	}
+}
+
+// Match - Returns an error if a string option is given a value that doesn't match the regular expression.
+// For slices every element is checked, for maps every value is checked.
+//
+// It will *panic* if the pattern doesn't compile.
+func (gopt *GetOpt) Match(pattern string) ModifyFn {
+	re := regexp.MustCompile(pattern)
+	return func(opt *option.Option) {
+		opt.AddValidator(func(opt *option.Option) error {
+			for _, s := range stringValues(opt.Value()) {
+				if !re.MatchString(s) {
+					return fmt.Errorf(text.ErrorValueDoesNotMatch, opt.UsedAlias, s, pattern)
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// MinLength - Returns an error if a slice or map option is called but ends up with fewer than n values.
+// Checked once all arguments have been parsed.
+func (gopt *GetOpt) MinLength(n int) ModifyFn {
+	return func(opt *option.Option) {
+		opt.MinLength = n
+	}
+}
+
+// MaxLength - Returns an error if a slice or map option ends up with more than n values.
+// Checked once all arguments have been parsed.
+func (gopt *GetOpt) MaxLength(n int) ModifyFn {
+	return func(opt *option.Option) {
+		opt.MaxLength = n
+	}
+}
+
+// Validate - Runs fn with the option value after it is saved.
+// The value has the same type as the one returned by `opt.Value(name)`.
+// For example:
+//
+//     opt.String("host", "", opt.Validate(func(value interface{}) error {
+//         if strings.Contains(value.(string), ":") {
+//             return fmt.Errorf("port not allowed")
+//         }
+//         return nil
+//     }))
+//
+// The returned error is wrapped with the name of the option.
+func (gopt *GetOpt) Validate(fn func(value interface{}) error) ModifyFn {
+	return func(opt *option.Option) {
+		opt.AddValidator(func(opt *option.Option) error {
+			if err := fn(opt.Value()); err != nil {
+				return fmt.Errorf(text.ErrorValidation, opt.UsedAlias, err)
+			}
+			return nil
+		})
+	}
+}
+
+// numericValues - Returns the numeric values held by v.
+// Slices return all their elements, non numeric values return an empty list.
+func numericValues(v interface{}) []reflect.Value {
+	r := reflect.ValueOf(v)
+	values := []reflect.Value{r}
+	if r.Kind() == reflect.Slice {
+		values = []reflect.Value{}
+		for i := 0; i < r.Len(); i++ {
+			values = append(values, r.Index(i))
+		}
+	}
+	nn := []reflect.Value{}
+	for _, e := range values {
+		switch e.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			nn = append(nn, e)
+		}
+	}
+	return nn
+}
+
+// compareNumber - Returns -1, 0 or 1 when the numeric value v is less than, equal to or greater than n.
+// Integers are compared as integers, converting them to float64 would round values above 2^53.
+func compareNumber(v reflect.Value, n float64) int {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n >= math.MaxInt64 {
+			return -1
+		}
+		if n < math.MinInt64 {
+			return 1
+		}
+		return compareInteger(v.Int() < int64(math.Floor(n)), v.Int() > int64(math.Floor(n)), n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n >= math.MaxUint64 {
+			return -1
+		}
+		if n < 0 {
+			return 1
+		}
+		return compareInteger(v.Uint() < uint64(math.Floor(n)), v.Uint() > uint64(math.Floor(n)), n)
+	}
+	switch f := v.Float(); {
+	case f < n:
+		return -1
+	case f > n:
+		return 1
+	}
+	return 0
+}
+
+// compareInteger - Returns the comparison of an integer with n given the comparison with floor(n).
+// An integer equal to floor(n) is less than n when n has a fractional part.
+func compareInteger(less, greater bool, n float64) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	case math.Floor(n) < n:
+		return -1
+	}
+	return 0
+}
+
+// stringValues - Returns the string values held by v.
+// Slices return all their elements and maps return all their values.
+func stringValues(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case map[string]string:
+		ss := []string{}
+		for _, e := range v {
+			ss = append(ss, e)
+		}
+		sort.Strings(ss)
+		return ss
+	}
+	return []string{}
+}
@@ -0,0 +1,182 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DavidGamba/go-getoptions/text"
+)
+
+func TestValidators(t *testing.T) {
+	define := func() *GetOpt {
+		opt := New()
+		opt.Int("port", 8080, opt.Alias("p"), opt.Min(1), opt.Max(65535))
+		opt.Float64("ratio", 0.5, opt.Min(0), opt.Max(1))
+		opt.IntSlice("ii", 1, 3, opt.Max(10))
+		Opt[uint](opt, "uint", 1, opt.Max(5))
+		opt.String("name", "", opt.Match(`^[a-z]+$`))
+		opt.StringSlice("ss", 1, 3, opt.Match(`^[a-z]+$`), opt.MinLength(2), opt.MaxLength(3))
+		opt.StringMap("m", 1, 3, opt.Match(`^[0-9]+$`))
+		opt.Increment("v", 0, opt.Max(2))
+		opt.String("host", "", opt.Validate(func(value interface{}) error {
+			if strings.Contains(value.(string), ":") {
+				return fmt.Errorf("port not allowed")
+			}
+			return nil
+		}))
+		return opt
+	}
+
+	opt := define()
+	_, err := opt.Parse([]string{
+		"-p", "1",
+		"--ratio", "1",
+		"--ii", "1..3",
+		"--uint", "5",
+		"--name", "abc",
+		"--ss", "a", "--ss", "b",
+		"--m", "k=1",
+		"-v", "-v",
+		"--host", "localhost",
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(opt.Value("ss"), []string{"a", "b"}) {
+		t.Errorf("Unexpected value: %v", opt.Value("ss"))
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"min int", []string{"-p", "0"}, fmt.Sprintf(text.ErrorValueLessThanMin, "p", 0, 1)},
+		{"max int", []string{"--port=65536"}, fmt.Sprintf(text.ErrorValueGreaterThanMax, "port", 65536, 65535)},
+		{"min float", []string{"--ratio=-0.1"}, fmt.Sprintf(text.ErrorValueLessThanMin, "ratio", -0.1, 0)},
+		{"max float", []string{"--ratio", "1.5"}, fmt.Sprintf(text.ErrorValueGreaterThanMax, "ratio", 1.5, 1)},
+		{"max int slice", []string{"--ii", "9..11"}, fmt.Sprintf(text.ErrorValueGreaterThanMax, "ii", 11, 10)},
+		{"max uint", []string{"--uint", "6"}, fmt.Sprintf(text.ErrorValueGreaterThanMax, "uint", 6, 5)},
+		{"match", []string{"--name", "ABC"}, fmt.Sprintf(text.ErrorValueDoesNotMatch, "name", "ABC", `^[a-z]+$`)},
+		{"match slice", []string{"--ss", "a", "B"}, fmt.Sprintf(text.ErrorValueDoesNotMatch, "ss", "B", `^[a-z]+$`)},
+		{"match map", []string{"--m", "k=v"}, fmt.Sprintf(text.ErrorValueDoesNotMatch, "m", "v", `^[0-9]+$`)},
+		{"min length", []string{"--ss", "a"}, fmt.Sprintf(text.ErrorTooFewValues, "ss", 1, 2)},
+		{"max length", []string{"--ss", "a", "b", "--ss", "c", "d"}, fmt.Sprintf(text.ErrorTooManyValues, "ss", 4, 3)},
+		{"max increment", []string{"-v", "-v", "-v"}, fmt.Sprintf(text.ErrorValueGreaterThanMax, "v", 3, 2)},
+		{"validate", []string{"--host", "localhost:8080"}, fmt.Sprintf(text.ErrorValidation, "host", "port not allowed")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := define()
+			_, err := opt.Parse(tt.args)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Error string didn't match expected value:\ngot: %v\nexp: %s", err, tt.err)
+			}
+		})
+	}
+
+	t.Run("env", func(t *testing.T) {
+		os.Setenv("_get_opt_env_port", "0")
+		defer os.Unsetenv("_get_opt_env_port")
+		opt := New()
+		opt.Int("port", 8080, opt.GetEnv("_get_opt_env_port"), opt.Min(1))
+		_, err := opt.Parse([]string{})
+		expected := fmt.Sprintf(text.ErrorValueLessThanMin, "_get_opt_env_port", 0, 1)
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		// CLI values take precedence over the env var
+		_, err = opt.Parse([]string{"--port", "80"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("large integers", func(t *testing.T) {
+		opt := New()
+		Opt[int64](opt, "i64", 0, opt.Max(1<<53))
+		Opt[uint64](opt, "u64", 0, opt.Min(1<<63))
+		_, err := opt.Parse([]string{"--i64", "9007199254740993"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorValueGreaterThanMax, "i64", 9007199254740993, float64(1<<53)) {
+			t.Errorf("Error string didn't match expected value: %v", err)
+		}
+		_, err = opt.Parse([]string{"--i64", "9007199254740992", "--u64", "18446744073709551615"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("duration panic", func(t *testing.T) {
+		for _, fn := range []func(*GetOpt) ModifyFn{
+			func(opt *GetOpt) ModifyFn { return opt.Min(1) },
+			func(opt *GetOpt) ModifyFn { return opt.Max(1) },
+		} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("duration option did not panic")
+					}
+				}()
+				opt := New()
+				opt.DurationSlice("wait", 1, 1, fn(opt))
+			}()
+		}
+	})
+
+	t.Run("match panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("wrong pattern did not panic")
+			}
+		}()
+		opt := New()
+		opt.String("name", "", opt.Match(`[`))
+	})
+}
+
+func TestCompareNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		n        float64
+		expected int
+	}{
+		{"int less", 1, 2, -1},
+		{"int equal", 2, 2, 0},
+		{"int greater", 3, 2, 1},
+		{"int fraction", 2, 2.5, -1},
+		{"int negative fraction", -3, -2.5, -1},
+		{"int64 above float precision", int64(1<<53 + 1), 1 << 53, 1},
+		{"int64 max bound", int64(math.MaxInt64), math.MaxInt64, -1},
+		{"int64 min bound", int64(math.MinInt64), -1e19, 1},
+		{"uint less", uint(1), 2, -1},
+		{"uint equal", uint(2), 2, 0},
+		{"uint greater", uint(3), 2, 1},
+		{"uint fraction", uint(2), 2.5, -1},
+		{"uint64 above float precision", uint64(1<<63 + 1), 1 << 63, 1},
+		{"uint64 max bound", uint64(math.MaxUint64), math.MaxUint64, -1},
+		{"uint negative bound", uint(0), -1, 1},
+		{"float less", 1.5, 2, -1},
+		{"float equal", 2.0, 2, 0},
+		{"float greater", float32(2.5), 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareNumber(reflect.ValueOf(tt.value), tt.n); got != tt.expected {
+				t.Errorf("got = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}